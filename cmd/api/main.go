@@ -1,11 +1,19 @@
 package main
 
 import (
+	"context"
 	"ewallet-service/config"
 	"ewallet-service/internal/handler"
+	"ewallet-service/internal/jwtkeys"
+	"ewallet-service/internal/mailer"
 	"ewallet-service/internal/middleware"
+	"ewallet-service/internal/model"
 	"ewallet-service/internal/repository"
 	"ewallet-service/internal/usecase"
+	"log"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,32 +21,200 @@ import (
 func main() {
 	config.ConnectDB()
 
+	// DI Auth (sessions & tokens)
+	keys, err := jwtkeys.FromEnv()
+	if err != nil {
+		log.Fatal("Gagal memuat kunci JWT: ", err)
+	}
+	sessionRepo := repository.NewSessionRepository(config.DB)
+	authUsecase := usecase.NewAuthUsecase(sessionRepo, keys)
+	jwksHandler := handler.NewJWKSHandler(keys)
+	go purgeRevokedTokens(authUsecase, time.Hour)
+
+	// DI Login attempts (brute-force protection)
+	auditRepo := repository.NewAuditRepository(config.DB)
+	attemptRepo := repository.NewLoginAttemptRepository(config.DB)
+	attemptUsecase := usecase.NewLoginAttemptUsecase(attemptRepo, auditRepo)
+	go purgeLoginAttempts(attemptUsecase, time.Hour)
+
 	// DI User
 	userRepo := repository.NewUserRepository(config.DB)
-	userUsecase := usecase.NewUserUsecase(userRepo)
+	userUsecase := usecase.NewUserUsecase(userRepo, authUsecase)
+	userUsecase.Attempts = attemptUsecase
 	userHandler := handler.NewUserHandler(userUsecase)
 
+	// DI Rate limits (fixed windows in the rate_limits table, shared by every instance)
+	rateLimiter := usecase.NewRateLimitUsecase(repository.NewRateLimitRepository(config.DB))
+
+	// DI Account (email verification & password reset)
+	mail, err := mailer.FromEnv()
+	if err != nil {
+		log.Fatal("Konfigurasi email tidak valid: ", err)
+	}
+	actionTokenRepo := repository.NewActionTokenRepository(config.DB)
+	accountUsecase := usecase.NewAccountUsecase(userRepo, actionTokenRepo, sessionRepo, mail, keys)
+	accountUsecase.Attempts = attemptUsecase
+	accountUsecase.RateLimit = rateLimiter
+	forgotLimits := []gin.HandlerFunc{
+		middleware.RateLimit(rateLimiter, "password_forgot", 5, time.Minute, middleware.PerIP),
+		middleware.RateLimit(rateLimiter, "password_forgot", 20, time.Hour, middleware.PerIP),
+	}
+	userUsecase.Account = accountUsecase
+	accountHandler := handler.NewAccountHandler(accountUsecase)
+
 	// DI Transaction
 	trxRepo := repository.NewTransactionRepository(config.DB)
-	trxUsecase := usecase.NewTransactionUsecase(trxRepo)
+	trxUsecase := usecase.NewTransactionUsecase(trxRepo, userUsecase)
+	trxUsecase.RequirePINForTopUp = os.Getenv("REQUIRE_PIN_FOR_TOPUP") == "true"
+	trxUsecase.OTP = userUsecase
+	if threshold := os.Getenv("TRANSFER_2FA_THRESHOLD"); threshold != "" {
+		amount, err := model.ParseMoney(threshold)
+		if err != nil {
+			log.Fatal("TRANSFER_2FA_THRESHOLD tidak valid: ", err)
+		}
+		trxUsecase.TwoFactorThreshold = amount
+	}
+	trxUsecase.Limits = model.DefaultLimitTiers()
+	if path := os.Getenv("TRANSACTION_LIMITS_FILE"); path != "" {
+		tiers, err := usecase.LoadLimitTiers(path)
+		if err != nil {
+			log.Fatal("TRANSACTION_LIMITS_FILE tidak valid: ", err)
+		}
+		trxUsecase.Limits = tiers
+	}
+	trxUsecase.Fees = model.DefaultFeeSchedule()
+	if path := os.Getenv("FEE_SCHEDULE_FILE"); path != "" {
+		schedule, err := usecase.LoadFeeSchedule(path)
+		if err != nil {
+			log.Fatal("FEE_SCHEDULE_FILE tidak valid: ", err)
+		}
+		trxUsecase.Fees = schedule
+	}
 	trxHandler := handler.NewTransactionHandler(trxUsecase)
 
+	// DI Pockets (named wallets next to the primary one)
+	pocketRepo := repository.NewPocketRepository(config.DB)
+	pocketUsecase := usecase.NewPocketUsecase(pocketRepo, trxRepo, userUsecase)
+	pocketHandler := handler.NewPocketHandler(pocketUsecase)
+
+	// wallet inquiries and transfer quotes reveal who owns a wallet number,
+	// so both share one budget against enumeration
+	inquiryLimits := []gin.HandlerFunc{
+		middleware.RateLimit(rateLimiter, "inquiry", 10, time.Minute, middleware.PerUser),
+		middleware.RateLimit(rateLimiter, "inquiry", 100, 24*time.Hour, middleware.PerUser),
+		middleware.RateLimit(rateLimiter, "inquiry", 30, time.Minute, middleware.PerIP),
+	}
+
+	// DI Admin (back office)
+	adminRepo := repository.NewAdminRepository(config.DB)
+	adminUsecase := usecase.NewAdminUsecase(adminRepo, sessionRepo, auditRepo, trxUsecase)
+	adminHandler := handler.NewAdminHandler(adminUsecase)
+
+	// DI Idempotency
+	idemRepo := repository.NewIdempotencyRepository(config.DB)
+	idemUsecase := usecase.NewIdempotencyUsecase(idemRepo)
+	idempotency := middleware.IdempotencyMiddleware(idemUsecase)
+	go purgeIdempotencyKeys(idemUsecase, time.Hour)
+
 	r := gin.Default()
+	// c.ClientIP() keys the login lockout and the per-IP rate limits: X-Forwarded-For is only
+	// believed from the proxies listed here, by default from none
+	if err := r.SetTrustedProxies(trustedProxies()); err != nil {
+		log.Fatal("TRUSTED_PROXIES tidak valid: ", err)
+	}
+	r.Use(middleware.Language())
+
+	r.GET("/.well-known/jwks.json", jwksHandler.Get)
 
 	api := r.Group("/api/v1")
 	{
 		api.POST("/register", userHandler.Register)
 		api.POST("/login", userHandler.Login)
+		api.POST("/login/2fa", userHandler.LoginTwoFactor)
+		api.POST("/token/refresh", userHandler.RefreshToken)
+		api.POST("/email/verify", accountHandler.VerifyEmail)
+		api.POST("/password/forgot", append(forgotLimits, accountHandler.ForgotPassword)...)
+		api.POST("/password/reset", accountHandler.ResetPassword)
 
-		protected := api.Group("/", middleware.AuthMiddleware())
+		protected := api.Group("/", middleware.AuthMiddleware(authUsecase))
 		{
-			protected.POST("/topup", trxHandler.TopUp)
-			protected.POST("/transfer", trxHandler.Transfer)
+			protected.POST("/logout", userHandler.Logout)
+			protected.POST("/email/verify/resend", accountHandler.ResendVerification)
+			protected.POST("/pin", userHandler.SetPIN)
+			protected.PUT("/pin", userHandler.ChangePIN)
+			protected.POST("/2fa/enroll", userHandler.EnrollTOTP)
+			protected.POST("/2fa/confirm", userHandler.ConfirmTOTP)
+			protected.POST("/2fa/disable", userHandler.DisableTOTP)
+			protected.POST("/topup", idempotency, trxHandler.TopUp)
+			protected.POST("/transfer", idempotency, trxHandler.Transfer)
+			protected.POST("/transfers/quote", append(inquiryLimits, trxHandler.QuoteTransfer)...)
+			protected.GET("/wallets/:number/inquiry", append(inquiryLimits, trxHandler.WalletInquiry)...)
+			protected.POST("/transfers/confirm", idempotency, trxHandler.ConfirmTransfer)
+			protected.GET("/transfers/:reference", trxHandler.TransferDetail)
 			protected.GET("/transactions", trxHandler.HistoryTransaction)
+			protected.GET("/transactions/:id", trxHandler.TransactionDetail)
+			protected.GET("/statements", trxHandler.Statement)
 			protected.GET("/balance", userHandler.GetBalance)
+			protected.GET("/pockets", pocketHandler.ListPockets)
+			protected.POST("/pockets", pocketHandler.CreatePocket)
+			protected.POST("/pockets/move", idempotency, pocketHandler.MovePocketFunds)
+			protected.GET("/limits", trxHandler.Limits)
+			protected.GET("/fees/quote", trxHandler.QuoteFee)
 
 		}
+
+		admin := api.Group("/admin", middleware.AuthMiddleware(authUsecase), middleware.RequireRole(model.RoleSupport, model.RoleAdmin))
+		{
+			admin.GET("/users", adminHandler.SearchUsers)
+			admin.GET("/users/:id", adminHandler.GetUser)
+			admin.GET("/wallets/:number", adminHandler.GetWallet)
+			admin.GET("/wallets/:number/transactions", adminHandler.WalletHistory)
+			admin.PUT("/wallets/:number/status", adminHandler.ChangeWalletStatus)
+			admin.POST("/users/:id/freeze", adminHandler.FreezeUser)
+			admin.POST("/users/:id/unfreeze", middleware.RequireRole(model.RoleAdmin), adminHandler.UnfreezeUser)
+			admin.PUT("/users/:id/role", middleware.RequireRole(model.RoleAdmin), adminHandler.ChangeRole)
+		}
 	}
 
 	r.Run(":8080")
 }
+
+// trustedProxies reads TRUSTED_PROXIES, a comma-separated list of IPs or CIDRs
+// (e.g. "10.0.0.0/8,192.168.1.10"). Empty means no proxy is trusted.
+func trustedProxies() []string {
+	var proxies []string
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
+}
+
+// purgeRevokedTokens keeps revoked_tokens small: once a revoked token has expired, its
+// expiry alone rejects it.
+func purgeRevokedTokens(u *usecase.AuthUsecase, every time.Duration) {
+	for range time.Tick(every) {
+		if _, err := u.PurgeRevokedTokens(context.Background()); err != nil {
+			log.Printf("Gagal membersihkan revoked_tokens: %v", err)
+		}
+	}
+}
+
+// purgeIdempotencyKeys drops the keys past IdempotencyKeyTTL, with their stored responses.
+func purgeIdempotencyKeys(u *usecase.IdempotencyUsecase, every time.Duration) {
+	for range time.Tick(every) {
+		if _, err := u.PurgeExpired(context.Background()); err != nil {
+			log.Printf("Gagal membersihkan idempotency_keys: %v", err)
+		}
+	}
+}
+
+// purgeLoginAttempts drops failure counters whose window has passed and that lock nothing.
+func purgeLoginAttempts(u *usecase.LoginAttemptUsecase, every time.Duration) {
+	for range time.Tick(every) {
+		if _, err := u.PurgeExpired(context.Background()); err != nil {
+			log.Printf("Gagal membersihkan login_attempts: %v", err)
+		}
+	}
+}
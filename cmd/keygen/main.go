@@ -0,0 +1,91 @@
+package main
+
+import (
+	"ewallet-service/internal/jwtkeys"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// keygen writes a new JWT signing key as <dir>/<kid>.pem.
+//
+//	go run ./cmd/keygen -dir keys                 # Ed25519, kid = today's date
+//	go run ./cmd/keygen -dir keys -alg RS256 -kid 2026-10-rsa
+//	go run ./cmd/keygen -dir keys -retire 2026-09 # keep only the public half of an old key
+//
+// Rotation: add the new key, deploy (it is now published in the JWKS and accepted), then switch
+// JWT_SIGNING_KID to it. Retire the old key once its last tokens expired (24 hours covers
+// email links), and delete it later.
+func main() {
+	dir := flag.String("dir", "keys", "key directory (JWT_KEYS_DIR)")
+	alg := flag.String("alg", jwtkeys.AlgEdDSA, "EdDSA or RS256")
+	kid := flag.String("kid", time.Now().Format("2006-01-02"), "key id, also the file name")
+	bits := flag.Int("bits", 3072, "RSA key size")
+	retire := flag.String("retire", "", "replace the private key of this kid by its public key")
+	flag.Parse()
+
+	if *retire != "" {
+		retireKey(*dir, *retire)
+		return
+	}
+
+	var key *jwtkeys.Key
+	var err error
+	switch *alg {
+	case jwtkeys.AlgEdDSA:
+		key, err = jwtkeys.GenerateEd25519(*kid)
+	case jwtkeys.AlgRS256:
+		key, err = jwtkeys.GenerateRSA(*kid, *bits)
+	default:
+		log.Fatalf("Algoritma tidak dikenal: %s (pakai EdDSA atau RS256)", *alg)
+	}
+	if err != nil {
+		log.Fatalf("Gagal membuat kunci: %v", err)
+	}
+
+	data, err := key.MarshalPrivatePEM()
+	if err != nil {
+		log.Fatalf("Gagal encode kunci: %v", err)
+	}
+
+	if err := os.MkdirAll(*dir, 0o700); err != nil {
+		log.Fatalf("Gagal membuat folder: %v", err)
+	}
+	path := filepath.Join(*dir, *kid+".pem")
+	// O_EXCL: never overwrite a key that may still be signing
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		log.Fatalf("Gagal menulis kunci: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		log.Fatalf("Gagal menulis kunci: %v", err)
+	}
+
+	fmt.Printf("🔑 %s kunci %s ditulis ke %s\n", key.Algorithm, *kid, path)
+}
+
+func retireKey(dir, kid string) {
+	path := filepath.Join(dir, kid+".pem")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Gagal membaca kunci: %v", err)
+	}
+
+	key, err := jwtkeys.ParsePEM(kid, data)
+	if err != nil {
+		log.Fatalf("Gagal membaca kunci: %v", err)
+	}
+	public, err := key.MarshalPublicPEM()
+	if err != nil {
+		log.Fatalf("Gagal encode kunci publik: %v", err)
+	}
+
+	if err := os.WriteFile(path, public, 0o600); err != nil {
+		log.Fatalf("Gagal menulis kunci: %v", err)
+	}
+	fmt.Printf("🗄  kunci %s sekarang hanya untuk verifikasi\n", kid)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"ewallet-service/config"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"ewallet-service/internal/usecase"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+)
+
+// reconcile recomputes every wallet balance from its TOPUP/TRANSFER_IN/TRANSFER_OUT/FEE history
+// and from its ledger postings, and reports the wallets that do not match either.
+//
+//	go run ./cmd/reconcile -format csv -out report.csv
+//	go run ./cmd/reconcile -apply   # also write ADJUSTMENT rows for the mismatches
+//
+// Exits with status 1 when a discrepancy is left after the run: any without -apply, and with it
+// those that were not adjusted or whose ledger does not match.
+func main() {
+	format := flag.String("format", "json", "report format: json or csv")
+	out := flag.String("out", "", "write the report to this file instead of stdout")
+	apply := flag.Bool("apply", false, "write adjustment entries for every discrepancy")
+	flag.Parse()
+
+	if *format != "json" && *format != "csv" {
+		log.Fatalf("Format tidak dikenal: %s (pakai json atau csv)", *format)
+	}
+
+	config.ConnectDB()
+
+	repo := repository.NewReconcileRepository(config.DB)
+	reconcileUsecase := usecase.NewReconcileUsecase(repo)
+
+	report, err := reconcileUsecase.Run(context.Background(), *apply)
+	if err != nil {
+		log.Fatalf("Gagal rekonsiliasi: %v", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("Gagal membuat file report: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if *format == "csv" {
+		err = writeCSV(w, report)
+	} else {
+		err = writeJSON(w, report)
+	}
+	if err != nil {
+		log.Fatalf("Gagal menulis report: %v", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "🔎 %d wallet dicek, %d selisih, total selisih %s\n",
+		report.WalletsChecked, len(report.Discrepancies), report.TotalDifference)
+
+	if report.Unresolved() > 0 {
+		os.Exit(1)
+	}
+}
+
+func writeJSON(w io.Writer, report model.ReconcileReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func writeCSV(w io.Writer, report model.ReconcileReport) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"wallet_id", "wallet_number", "balance", "history_balance", "ledger_balance", "difference", "transaction_count", "adjusted"})
+	for _, d := range report.Discrepancies {
+		cw.Write([]string{
+			strconv.Itoa(d.WalletID),
+			d.WalletNumber,
+			d.Balance.String(),
+			d.HistoryBalance.String(),
+			d.LedgerBalance.String(),
+			d.Difference.String(),
+			strconv.Itoa(d.TransactionCount),
+			strconv.FormatBool(d.Adjusted),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
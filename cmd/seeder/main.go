@@ -29,5 +29,5 @@ func main() {
 		log.Fatalf("Gagal seeding: %v", err)
 	}
 
-	fmt.Printf("🌱 Seeding success!\nUser: %s\nWallet: %s\nBalance: %.2f\n", dummyUser.Email, wallet.WalletNumber, wallet.Balance)
+	fmt.Printf("🌱 Seeding success!\nUser: %s\nWallet: %s\nBalance: %s\n", dummyUser.Email, wallet.WalletNumber, wallet.Balance)
 }
\ No newline at end of file
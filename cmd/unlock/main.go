@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"ewallet-service/config"
+	"ewallet-service/internal/repository"
+	"ewallet-service/internal/usecase"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+// unlock lifts a login lockout before it expires, e.g. after support verified the owner.
+//
+//	go run ./cmd/unlock -email budi@example.com -actor support:andi
+//
+// The unlock is written to the audit log with the given actor.
+func main() {
+	email := flag.String("email", "", "email of the locked account")
+	actor := flag.String("actor", "cli", "who unlocks the account, recorded in the audit log")
+	flag.Parse()
+
+	if *email == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	config.ConnectDB()
+
+	attemptUsecase := usecase.NewLoginAttemptUsecase(
+		repository.NewLoginAttemptRepository(config.DB),
+		repository.NewAuditRepository(config.DB),
+	)
+
+	if err := attemptUsecase.Unlock(context.Background(), *email, *actor); err != nil {
+		log.Fatalf("Gagal membuka kunci login: %v", err)
+	}
+
+	fmt.Printf("🔓 Login %s dibuka kembali\n", *email)
+}
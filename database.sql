@@ -3,24 +3,197 @@ CREATE TABLE users (
     name VARCHAR(100) NOT NULL,
     email VARCHAR(100) UNIQUE NOT NULL,
     password VARCHAR(255) NOT NULL,
+    pin_hash VARCHAR(255),
+    pin_failed_attempts INT NOT NULL DEFAULT 0,
+    pin_locked_until TIMESTAMP,
+    totp_secret VARCHAR(64),
+    totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    totp_last_step BIGINT,
+    otp_failed_attempts INT NOT NULL DEFAULT 0,
+    otp_locked_until TIMESTAMP,
+    email_verified_at TIMESTAMP,
+    role VARCHAR(20) NOT NULL DEFAULT 'customer' CHECK (role IN ('customer', 'merchant', 'support', 'admin')),
+    frozen_at TIMESTAMP,
+    frozen_reason TEXT,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+-- every wallet is a pocket of its user: the primary one is opened at registration and receives
+-- top-ups, further named pockets are opened with POST /pockets
 CREATE TABLE wallets (
     id SERIAL PRIMARY KEY,
-    user_id INT UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL DEFAULT 'Utama',
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
     balance DECIMAL(15, 2) DEFAULT 0.00,
     wallet_number VARCHAR(20) UNIQUE NOT NULL,
+    -- checked under the FOR UPDATE lock of every top-up and transfer
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'frozen', 'closed', 'debit_blocked', 'credit_blocked')),
+    status_reason TEXT,
+    status_changed_at TIMESTAMP,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP 
 );
 
+CREATE UNIQUE INDEX idx_wallets_primary ON wallets(user_id) WHERE is_primary;
+CREATE UNIQUE INDEX idx_wallets_user_name ON wallets(user_id, LOWER(name));
+
+-- double-entry ledger: every money movement is a journal entry whose postings sum to zero.
+-- wallets.balance is a cached copy of the sum of postings on the wallet's account.
+CREATE TABLE ledger_accounts (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) UNIQUE NOT NULL,
+    account_type VARCHAR(20) NOT NULL CHECK (account_type IN ('WALLET', 'SYSTEM')),
+    wallet_id INT UNIQUE REFERENCES wallets(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO ledger_accounts (code, account_type) VALUES
+    ('SYSTEM:TOPUP_FUNDING', 'SYSTEM'),
+    ('SYSTEM:FEE_INCOME', 'SYSTEM'),
+    ('SYSTEM:SUSPENSE', 'SYSTEM');
+
+CREATE TABLE journal_entries (
+    id SERIAL PRIMARY KEY,
+    entry_type VARCHAR(20) NOT NULL,
+    reference VARCHAR(50),
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE postings (
+    id SERIAL PRIMARY KEY,
+    journal_entry_id INT NOT NULL REFERENCES journal_entries(id),
+    account_id INT NOT NULL REFERENCES ledger_accounts(id),
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount <> 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_postings_account ON postings(account_id);
+
+-- one row per transfer, linking the TRANSFER_OUT and TRANSFER_IN rows under a ULID reference
+CREATE TABLE transfers (
+    id SERIAL PRIMARY KEY,
+    reference CHAR(26) UNIQUE NOT NULL,
+    sender_wallet_id INT NOT NULL REFERENCES wallets(id),
+    receiver_wallet_id INT NOT NULL REFERENCES wallets(id),
+    amount DECIMAL(15, 2) NOT NULL,
+    fee DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- short-lived transfer quotes: POST /transfers/quote shows recipient and fee, POST /transfers/confirm
+-- executes the quote once (used_at is set in the transfer's own database transaction)
+CREATE TABLE transfer_quotes (
+    id CHAR(26) PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_wallet_number VARCHAR(20) NOT NULL,
+    -- the sender's pocket; empty means the primary one
+    source_wallet_number VARCHAR(20) NOT NULL DEFAULT '',
+    amount DECIMAL(15, 2) NOT NULL,
+    fee DECIMAL(15, 2) NOT NULL,
+    description TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 CREATE TABLE transactions (
     id SERIAL PRIMARY KEY,
     wallet_id INT REFERENCES wallets(id),
-    transaction_type VARCHAR(20),
+    transaction_type VARCHAR(20) CHECK (transaction_type IN ('TOPUP', 'TRANSFER_IN', 'TRANSFER_OUT', 'FEE', 'ADJUSTMENT')),
     amount DECIMAL(15, 2) NOT NULL,
+    balance_after DECIMAL(15, 2),
     description TEXT,
+    transfer_id INT REFERENCES transfers(id),
+    journal_entry_id INT REFERENCES journal_entries(id),
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-);
\ No newline at end of file
+);
+
+-- history pages and the day/month sums behind the transaction limits
+CREATE INDEX idx_transactions_wallet_created ON transactions(wallet_id, created_at);
+
+CREATE TABLE idempotency_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    idem_key VARCHAR(255) NOT NULL,
+    endpoint VARCHAR(100) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    response_status INT NOT NULL DEFAULT 0,
+    response_body JSONB,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, endpoint, idem_key)
+);
+CREATE INDEX idx_idempotency_keys_created ON idempotency_keys(created_at);
+
+-- login sessions: each refresh rotates the token, a reused (already rotated) token revokes the session
+CREATE TABLE sessions (
+    id CHAR(26) PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE refresh_tokens (
+    token_hash CHAR(64) PRIMARY KEY,
+    session_id CHAR(26) NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- access tokens revoked before they expire (logout), checked by jti
+CREATE TABLE revoked_tokens (
+    jti CHAR(26) PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- single-use email tokens (verification, password reset); the token itself is a signed JWT
+CREATE TABLE action_tokens (
+    jti CHAR(26) PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(30) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 2FA recovery codes, stored as SHA-256 and usable once each
+CREATE TABLE recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, code_hash)
+);
+
+-- failed login counters, keyed by "account:<email>" or "ip:<address>"
+CREATE TABLE login_attempts (
+    attempt_key VARCHAR(255) PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP
+);
+CREATE INDEX idx_login_attempts_last_failure ON login_attempts(last_failure_at);
+
+-- fixed-window request counters of rate limited routes, keyed by "<name>:<user:id|ip:address>:<window>"
+CREATE TABLE rate_limits (
+    rate_key VARCHAR(255) PRIMARY KEY,
+    window_start TIMESTAMP NOT NULL,
+    hits INT NOT NULL
+);
+
+CREATE TABLE audit_logs (
+    id SERIAL PRIMARY KEY,
+    event VARCHAR(50) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    detail TEXT,
+    ip VARCHAR(45),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_logs_subject ON audit_logs(subject, created_at);
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/response"
+	"ewallet-service/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountHandler struct {
+	AccountUsecase *usecase.AccountUsecase
+}
+
+func NewAccountHandler(u *usecase.AccountUsecase) *AccountHandler {
+	return &AccountHandler{AccountUsecase: u}
+}
+
+func (h *AccountHandler) VerifyEmail(c *gin.Context) {
+	var req model.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "INVALID_INPUT", &req, err)
+		return
+	}
+
+	if err := h.AccountUsecase.VerifyEmail(c.Request.Context(), req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "EMAIL_VERIFIED"),
+	})
+}
+
+func (h *AccountHandler) ResendVerification(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	if err := h.AccountUsecase.ResendVerification(c.Request.Context(), userID.(int)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "VERIFICATION_RESENT"),
+	})
+}
+
+func (h *AccountHandler) ForgotPassword(c *gin.Context) {
+	var req model.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "INVALID_INPUT", &req, err)
+		return
+	}
+
+	if err := h.AccountUsecase.ForgotPassword(c.Request.Context(), req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// same answer whether the email is registered or not
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "PASSWORD_RESET_SENT"),
+	})
+}
+
+func (h *AccountHandler) ResetPassword(c *gin.Context) {
+	var req model.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "INPUT_NEW_PASSWORD", &req, err)
+		return
+	}
+
+	if err := h.AccountUsecase.ResetPassword(c.Request.Context(), req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "PASSWORD_RESET"),
+	})
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/response"
+	"ewallet-service/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler serves /api/v1/admin; the role checks are done by RequireRole on the routes.
+type AdminHandler struct {
+	AdminUsecase *usecase.AdminUsecase
+}
+
+func NewAdminHandler(u *usecase.AdminUsecase) *AdminHandler {
+	return &AdminHandler{AdminUsecase: u}
+}
+
+func (h *AdminHandler) SearchUsers(c *gin.Context) {
+	var search model.UserSearch
+	if err := c.ShouldBindQuery(&search); err != nil {
+		respondInvalidInput(c, "INVALID_FILTER", &search, err)
+		return
+	}
+
+	res, err := h.AdminUsecase.SearchUsers(c.Request.Context(), search)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "USERS_SHOWN"),
+		Data:    res,
+	})
+}
+
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.AdminUsecase.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "USER_SHOWN"),
+		Data:    res,
+	})
+}
+
+func (h *AdminHandler) GetWallet(c *gin.Context) {
+	res, err := h.AdminUsecase.GetWallet(c.Request.Context(), c.Param("number"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "WALLET_SHOWN"),
+		Data:    res,
+	})
+}
+
+func (h *AdminHandler) WalletHistory(c *gin.Context) {
+	var filter model.TransactionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondInvalidInput(c, "INVALID_FILTER", &filter, err)
+		return
+	}
+
+	res, err := h.AdminUsecase.WalletHistory(c.Request.Context(), c.Param("number"), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "HISTORY_SHOWN"),
+		Data:    res,
+	})
+}
+
+func (h *AdminHandler) FreezeUser(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req model.FreezeAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "INPUT_FREEZE", &req, err)
+		return
+	}
+
+	if err := h.AdminUsecase.Freeze(c.Request.Context(), actorClaims(c), userID, req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "ACCOUNT_FROZEN_SUCCESS"),
+	})
+}
+
+func (h *AdminHandler) UnfreezeUser(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.AdminUsecase.Unfreeze(c.Request.Context(), actorClaims(c), userID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "ACCOUNT_UNFROZEN_SUCCESS"),
+	})
+}
+
+func (h *AdminHandler) ChangeRole(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req model.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "INPUT_ROLE", &req, err)
+		return
+	}
+
+	res, err := h.AdminUsecase.ChangeRole(c.Request.Context(), actorClaims(c), userID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "ROLE_CHANGED"),
+		Data:    res,
+	})
+}
+
+func (h *AdminHandler) ChangeWalletStatus(c *gin.Context) {
+	var req model.ChangeWalletStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "INPUT_WALLET_STATUS", &req, err)
+		return
+	}
+
+	res, err := h.AdminUsecase.ChangeWalletStatus(c.Request.Context(), actorClaims(c), c.Param("number"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "WALLET_STATUS_CHANGED"),
+		Data:    res,
+	})
+}
+
+func userIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		respondInvalidInput(c, "INPUT_USER_ID", nil, nil)
+		return 0, false
+	}
+	return id, true
+}
+
+// the admin routes sit behind AuthMiddleware, so the claims are always there
+func actorClaims(c *gin.Context) *model.AccessClaims {
+	return c.MustGet("claims").(*model.AccessClaims)
+}
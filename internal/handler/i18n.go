@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"ewallet-service/internal/i18n"
+	"ewallet-service/internal/response"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// respondInvalidInput answers a request whose body, query or path could not be bound.
+// key picks the message from the catalog, err carries the binding details and may be nil,
+// req is what was bound into (it names the fields that rules like required_without refer to).
+func respondInvalidInput(c *gin.Context, key string, req interface{}, err error) {
+	res := response.WebResponse{
+		Status:  "fail",
+		Code:    "INVALID_INPUT",
+		Message: response.Translate(c, key),
+	}
+	if err != nil {
+		res.Error, res.Errors = bindingDetail(c, req, err)
+	}
+	c.JSON(http.StatusBadRequest, res)
+}
+
+// bindingDetail explains in the request's language why ShouldBind failed. Validation
+// failures are also returned per field; errors that are not about a field give nil.
+func bindingDetail(c *gin.Context, req interface{}, err error) (string, []response.FieldError) {
+	lang := response.Lang(c)
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]response.FieldError, len(validationErrs))
+		details := make([]string, len(validationErrs))
+		for i, fe := range validationErrs {
+			fields[i] = fieldError(lang, req, fe)
+			details[i] = fields[i].Message
+		}
+		return strings.Join(details, "; "), fields
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return i18n.Message(lang, "INVALID_JSON"), nil
+	}
+	return err.Error(), nil
+}
+
+func fieldError(lang string, req interface{}, fe validator.FieldError) response.FieldError {
+	res := response.FieldError{Field: fieldPath(fe), Rule: fe.Tag(), Param: fe.Param()}
+	field := res.Field
+
+	switch fe.Tag() {
+	case "required":
+		res.Message = i18n.Message(lang, "VALIDATION_REQUIRED", field)
+	case "required_without":
+		res.Param = siblingName(req, fe)
+		res.Message = i18n.Message(lang, "VALIDATION_REQUIRED_WITHOUT", field, res.Param)
+	case "min", "max":
+		key := "VALIDATION_" + strings.ToUpper(fe.Tag())
+		if fe.Kind().String() == "string" {
+			key += "_LENGTH"
+		}
+		res.Message = i18n.Message(lang, key, field, fe.Param())
+	case "money_min":
+		// money_min is how amounts are checked; to the client it is just a minimum
+		res.Rule = "min"
+		res.Message = i18n.Message(lang, "VALIDATION_MIN", field, fe.Param())
+	case "len":
+		res.Message = i18n.Message(lang, "VALIDATION_LEN", field, fe.Param())
+	case "numeric":
+		res.Message = i18n.Message(lang, "VALIDATION_NUMERIC", field)
+	case "email":
+		res.Message = i18n.Message(lang, "VALIDATION_EMAIL", field)
+	case "oneof":
+		res.Param = strings.ReplaceAll(fe.Param(), " ", ", ")
+		res.Message = i18n.Message(lang, "VALIDATION_ONEOF", field, res.Param)
+	case "wallet_number":
+		res.Message = i18n.Message(lang, "VALIDATION_WALLET_NUMBER", field)
+	case "datetime":
+		res.Param = strings.NewReplacer("2006", "YYYY", "01", "MM", "02", "DD").Replace(fe.Param())
+		res.Message = i18n.Message(lang, "VALIDATION_DATETIME", field, res.Param)
+	default:
+		res.Message = i18n.Message(lang, "VALIDATION_INVALID", field)
+	}
+	return res
+}
+
+// fieldPath is the field's path below the request struct, so nested fields stay
+// addressable: "TransferRequest.items[0].amount" -> "items[0].amount"
+func fieldPath(fe validator.FieldError) string {
+	if _, path, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return path
+	}
+	return fe.Field()
+}
+
+// siblingName is the client-facing name of the field a rule like required_without=OTP names:
+// the param is a Go field of the struct holding fe's field, found by walking req's type along
+// fe's namespace. The Go name is kept when it cannot be resolved.
+func siblingName(req interface{}, fe validator.FieldError) string {
+	t := reflect.TypeOf(req)
+	if t == nil {
+		return fe.Param()
+	}
+
+	// the first segment is the request type, the last one fe's own field
+	segments := strings.Split(fe.StructNamespace(), ".")
+	for _, segment := range segments[1 : len(segments)-1] {
+		name, _, _ := strings.Cut(segment, "[")
+		field, ok := elemType(t).FieldByName(name)
+		if !ok {
+			return fe.Param()
+		}
+		t = field.Type
+	}
+
+	parent := elemType(t)
+	if parent.Kind() != reflect.Struct {
+		return fe.Param()
+	}
+	field, ok := parent.FieldByName(fe.Param())
+	if !ok {
+		return fe.Param()
+	}
+	return clientFieldName(field)
+}
+
+// elemType steps through pointers, slices and maps to the type of the values inside
+func elemType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
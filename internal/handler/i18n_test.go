@@ -0,0 +1,138 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"ewallet-service/internal/handler"
+	"ewallet-service/internal/response"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// bindRegister sends a RegisterRequest like UserHandler.Register does, without a usecase behind it
+func bindRegister(body, acceptLanguage string) (*httptest.ResponseRecorder, response.WebResponse) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := handler.NewUserHandler(nil)
+	r.POST("/register", h.Register)
+
+	req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", acceptLanguage)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var res response.WebResponse
+	json.Unmarshal(w.Body.Bytes(), &res)
+	return w, res
+}
+
+func TestBindingErrors_Indonesian(t *testing.T) {
+	w, res := bindRegister(`{"name": "Budi", "email": "bukan-email", "password": "123"}`, "")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "INVALID_INPUT", res.Code)
+	assert.Equal(t, "Input tidak valid", res.Message)
+	assert.Equal(t, "email harus berupa alamat email yang valid; password minimal 6 karakter", res.Error)
+}
+
+func TestBindingErrors_English(t *testing.T) {
+	w, res := bindRegister(`{"email": "budi@example.com", "password": "rahasia123"}`, "en")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "Invalid input", res.Message)
+	assert.Equal(t, "name is required", res.Error)
+}
+
+func TestBindingErrors_MalformedJSON(t *testing.T) {
+	_, res := bindRegister(`{"email": `, "en")
+
+	assert.Equal(t, "Malformed JSON body", res.Error)
+}
+
+func TestBindingErrors_ListsEachField(t *testing.T) {
+	_, res := bindRegister(`{"name": "Budi", "email": "bukan-email", "password": "123"}`, "en")
+
+	assert.Equal(t, []response.FieldError{
+		{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+		{Field: "password", Rule: "min", Param: "6", Message: "password must be at least 6 characters"},
+	}, res.Errors)
+}
+
+func TestBindingErrors_MalformedJSONHasNoFields(t *testing.T) {
+	_, res := bindRegister(`{"email": `, "en")
+
+	assert.Empty(t, res.Errors)
+}
+
+func TestBindingErrors_MoneyMinIsReportedAsMin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/topup", func(c *gin.Context) { c.Set("userID", 1) }, handler.NewTransactionHandler(nil).TopUp)
+
+	req := httptest.NewRequest(http.MethodPost, "/topup", strings.NewReader(`{"amount": "500.00"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var res response.WebResponse
+	json.Unmarshal(w.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, "amount", res.Errors[0].Field)
+		assert.Equal(t, "min", res.Errors[0].Rule)
+		assert.Equal(t, "10000", res.Errors[0].Param)
+	}
+}
+
+func TestBindingErrors_WalletNumberTypo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/transfer", func(c *gin.Context) { c.Set("userID", 1) }, handler.NewTransactionHandler(nil).Transfer)
+
+	// 100123456780 with one digit mistyped
+	body := `{"target_wallet_number": "100123456880", "amount": "10000", "pin": "123456"}`
+	req := httptest.NewRequest(http.MethodPost, "/transfer", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var res response.WebResponse
+	json.Unmarshal(w.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, "target_wallet_number", res.Errors[0].Field)
+		assert.Equal(t, "wallet_number", res.Errors[0].Rule)
+		assert.Equal(t, "target_wallet_number is not a valid wallet number", res.Errors[0].Message)
+	}
+}
+
+func TestBindingErrors_RequiredWithoutNamesSiblingByJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/login/2fa", handler.NewUserHandler(nil).LoginTwoFactor)
+
+	// neither otp nor recovery_code
+	req := httptest.NewRequest(http.MethodPost, "/login/2fa", strings.NewReader(`{"challenge_token": "abc"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var res response.WebResponse
+	json.Unmarshal(w.Body.Bytes(), &res)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if assert.Len(t, res.Errors, 2) {
+		assert.Equal(t, "otp", res.Errors[0].Field)
+		assert.Equal(t, "required_without", res.Errors[0].Rule)
+		assert.Equal(t, "recovery_code", res.Errors[0].Param)
+		assert.Equal(t, "otp is required when recovery_code is empty", res.Errors[0].Message)
+		assert.Equal(t, "recovery_code", res.Errors[1].Field)
+		assert.Equal(t, "otp", res.Errors[1].Param)
+	}
+}
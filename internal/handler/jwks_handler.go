@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"ewallet-service/internal/jwtkeys"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSHandler struct {
+	Keys *jwtkeys.KeySet
+}
+
+func NewJWKSHandler(keys *jwtkeys.KeySet) *JWKSHandler {
+	return &JWKSHandler{Keys: keys}
+}
+
+// Get serves the public verification keys. It answers the bare RFC 7517 document instead of
+// a WebResponse, because that is what JWT libraries of other services fetch and parse.
+func (h *JWKSHandler) Get(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.Keys.JWKS())
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/response"
+	"ewallet-service/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PocketHandler struct {
+	PocketUsecase *usecase.PocketUsecase
+}
+
+func NewPocketHandler(u *usecase.PocketUsecase) *PocketHandler {
+	return &PocketHandler{PocketUsecase: u}
+}
+
+func (h *PocketHandler) ListPockets(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	res, err := h.PocketUsecase.ListPockets(c.Request.Context(), userID.(int))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "POCKETS_SHOWN"),
+		Data:    res,
+	})
+}
+
+func (h *PocketHandler) CreatePocket(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req model.CreatePocketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "INPUT_POCKET", &req, err)
+		return
+	}
+
+	res, err := h.PocketUsecase.CreatePocket(c.Request.Context(), userID.(int), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "POCKET_CREATED"),
+		Data:    res,
+	})
+}
+
+func (h *PocketHandler) MovePocketFunds(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req model.PocketMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "INPUT_POCKET_MOVE", &req, err)
+		return
+	}
+
+	res, err := h.PocketUsecase.MovePocketFunds(c.Request.Context(), userID.(int), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "POCKET_MOVED"),
+		Data:    res,
+	})
+}
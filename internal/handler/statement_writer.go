@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"ewallet-service/internal/model"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const statementDateFormat = "2006-01-02 15:04"
+
+// csvText neutralises free text (names, transfer descriptions chosen by the sender) so a spreadsheet
+// opening the statement shows it as text instead of evaluating it as a formula.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func writeStatementCSV(w io.Writer, st *model.Statement) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"wallet_number", st.WalletNumber})
+	cw.Write([]string{"owner_name", csvText(st.OwnerName)})
+	cw.Write([]string{"period", st.PeriodStart.Format("2006-01")})
+	cw.Write([]string{"opening_balance", st.OpeningBalance.String()})
+	cw.Write([]string{})
+	cw.Write([]string{"date", "transaction_id", "transaction_type", "description", "reference", "credit", "debit", "balance"})
+	for _, l := range st.Lines {
+		cw.Write([]string{
+			l.Date.Format(statementDateFormat),
+			strconv.Itoa(l.TransactionID),
+			l.TransactionType,
+			csvText(l.Description),
+			csvText(l.Reference),
+			l.Credit.String(),
+			l.Debit.String(),
+			l.Balance.String(),
+		})
+	}
+	cw.Write([]string{})
+	cw.Write([]string{"total_in", st.TotalIn.String()})
+	cw.Write([]string{"total_out", st.TotalOut.String()})
+	cw.Write([]string{"closing_balance", st.ClosingBalance.String()})
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeStatementPDF(w io.Writer, st *model.Statement) error {
+	row := "%-16s %-12s %-24s %15s %15s %15s"
+
+	var lines []string
+	lines = append(lines,
+		"ACCOUNT STATEMENT",
+		"",
+		"Wallet   : "+st.WalletNumber,
+		"Name     : "+st.OwnerName,
+		"Period   : "+st.PeriodStart.Format("January 2006"),
+		"Generated: "+st.GeneratedAt.Format(statementDateFormat),
+		"",
+		fmt.Sprintf("%-54s %47s", "Opening balance", st.OpeningBalance),
+		"",
+		fmt.Sprintf(row, "Date", "Type", "Description", "Credit", "Debit", "Balance"),
+		strings.Repeat("-", 102),
+	)
+	for _, l := range st.Lines {
+		lines = append(lines, fmt.Sprintf(row,
+			l.Date.Format(statementDateFormat),
+			truncate(l.TransactionType, 12),
+			truncate(l.Description, 24),
+			blankIfZero(l.Credit),
+			blankIfZero(l.Debit),
+			l.Balance,
+		))
+	}
+	lines = append(lines,
+		strings.Repeat("-", 102),
+		fmt.Sprintf(row, "", "", "Total", st.TotalIn, st.TotalOut, ""),
+		"",
+		fmt.Sprintf("%-54s %47s", "Closing balance", st.ClosingBalance),
+	)
+
+	_, err := w.Write(renderPDF(lines))
+	return err
+}
+
+func blankIfZero(m model.Money) string {
+	if m.IsZero() {
+		return ""
+	}
+	return m.String()
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "~"
+}
+
+// renderPDF lays out monospaced text lines on A4 landscape pages. It only uses the
+// built-in Courier font, so no font files or external libraries are needed.
+func renderPDF(lines []string) []byte {
+	const (
+		pageWidth    = 842
+		pageHeight   = 595
+		margin       = 40
+		fontSize     = 9
+		leading      = 12
+		linesPerPage = (pageHeight - 2*margin) / leading
+	)
+
+	var pages [][]string
+	for len(lines) > linesPerPage {
+		pages = append(pages, lines[:linesPerPage])
+		lines = lines[linesPerPage:]
+	}
+	pages = append(pages, lines)
+
+	// objects: 1 catalog, 2 page tree, 3 font, then a (page, content) pair per page
+	var objects []string
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range pages {
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %d Td\n", fontSize, leading, margin, pageHeight-margin)
+		for _, l := range page {
+			fmt.Fprintf(&content, "(%s) '\n", pdfEscape(l))
+		}
+		fmt.Fprintf(&content, "ET\nBT /F1 8 Tf %d %d Td (Page %d of %d) Tj ET\n", pageWidth-margin-80, margin/2, i+1, len(pages))
+
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// pdfEscape escapes string delimiters and replaces characters outside printable ASCII,
+// which the standard Courier font cannot show reliably.
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"ewallet-service/internal/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteStatementCSV_NeutralisesFormulas(t *testing.T) {
+	// arrange: the description was chosen by whoever sent the transfer
+	st := &model.Statement{
+		WalletNumber: "8001234567",
+		OwnerName:    "@Budi",
+		PeriodStart:  time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		Lines: []model.StatementLine{
+			{TransactionID: 1, TransactionType: model.TransactionTypeTransferIn, Description: `=HYPERLINK("http://evil","klik")`, Reference: "01JAAAAAAAAAAAAAAAAAAAAAAA"},
+			{TransactionID: 2, TransactionType: model.TransactionTypeTopUp, Description: "Topup Saldo via API"},
+		},
+	}
+	var buf bytes.Buffer
+
+	// act
+	err := writeStatementCSV(&buf, st)
+
+	// assert
+	assert.NoError(t, err)
+	r := csv.NewReader(&buf)
+	r.FieldsPerRecord = -1
+	records, _ := r.ReadAll()
+	assert.Equal(t, "'@Budi", records[1][1])
+	assert.Equal(t, `'=HYPERLINK("http://evil","klik")`, records[5][3])
+	assert.Equal(t, "01JAAAAAAAAAAAAAAAAAAAAAAA", records[5][4])
+	assert.Equal(t, "Topup Saldo via API", records[6][3])
+}
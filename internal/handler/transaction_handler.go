@@ -1,11 +1,17 @@
 package handler
 
 import (
+	"bytes"
 	"ewallet-service/internal/model"
+	"ewallet-service/internal/response"
 	"ewallet-service/internal/usecase"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/oklog/ulid/v2"
 )
 
 type TransactionHandler struct {
@@ -19,35 +25,25 @@ func NewTransactionHandler(u *usecase.TransactionUsecase) *TransactionHandler {
 func (h *TransactionHandler) TopUp(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, WebResponse{
-			Status:  "fail",
-			Message: "User ID tidak ditemukan di token",
-		})
+		response.Error(c, response.ErrUnauthorized)
 		return
 	}
 
 	var req model.TopUpRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, WebResponse{
-			Status:  "fail",
-			Message: "Input tidak valid (min: 10000)",
-			Error:   err.Error(),
-		})
+		respondInvalidInput(c, "INPUT_TOPUP", &req, err)
 		return
 	}
 
 	res, err := h.TransactionUsecase.TopUp(c.Request.Context(), userID.(int), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, WebResponse{
-			Status:  "error",
-			Message: err.Error(),
-		})
+		response.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, WebResponse{
+	c.JSON(http.StatusOK, response.WebResponse{
 		Status:  "success",
-		Message: "Topup berhasil",
+		Message: response.Translate(c, "TOPUP_SUCCESS"),
 		Data:    res,
 	})
 }
@@ -55,35 +51,104 @@ func (h *TransactionHandler) TopUp(c *gin.Context) {
 func (h *TransactionHandler) Transfer(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, WebResponse{
-			Status:  "fail",
-			Message: "Unauthorized",
-		})
+		response.Error(c, response.ErrUnauthorized)
 		return
 	}
 
 	var req model.TransferRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, WebResponse{
-			Status:  "fail",
-			Message: "Input tidak valid",
-			Error:   err.Error(),
-		})
+		respondInvalidInput(c, "INVALID_INPUT", &req, err)
 		return
 	}
 
 	res, err := h.TransactionUsecase.Transfer(c.Request.Context(), userID.(int), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, WebResponse{
-			Status:  "error",
-			Message: err.Error(),
-		})
+		response.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, WebResponse{
+	c.JSON(http.StatusOK, response.WebResponse{
 		Status:  "success",
-		Message: "Transfer berhasil",
+		Message: response.Translate(c, "TRANSFER_SUCCESS"),
+		Data:    res,
+	})
+}
+
+func (h *TransactionHandler) QuoteTransfer(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req model.TransferQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "INVALID_INPUT", &req, err)
+		return
+	}
+
+	res, err := h.TransactionUsecase.QuoteTransfer(c.Request.Context(), userID.(int), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "TRANSFER_QUOTED"),
+		Data:    res,
+	})
+}
+
+func (h *TransactionHandler) ConfirmTransfer(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req model.TransferConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "INVALID_INPUT", &req, err)
+		return
+	}
+	req.QuoteID = strings.ToUpper(req.QuoteID)
+
+	res, err := h.TransactionUsecase.ConfirmTransfer(c.Request.Context(), userID.(int), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "TRANSFER_SUCCESS"),
+		Data:    res,
+	})
+}
+
+func (h *TransactionHandler) WalletInquiry(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req model.WalletInquiryRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		respondInvalidInput(c, "INPUT_WALLET_INQUIRY", &req, err)
+		return
+	}
+
+	res, err := h.TransactionUsecase.InquireWallet(c.Request.Context(), userID.(int), req.Number)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "WALLET_INQUIRY_SHOWN"),
 		Data:    res,
 	})
 }
@@ -91,26 +156,163 @@ func (h *TransactionHandler) Transfer(c *gin.Context) {
 func (h *TransactionHandler) HistoryTransaction(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, WebResponse{
-			Status:  "fail",
-			Message: "Unauthorized",
-		})
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	var filter model.TransactionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondInvalidInput(c, "INVALID_FILTER", &filter, err)
+		return
+	}
+
+	res, err := h.TransactionUsecase.GetHistory(c.Request.Context(), userID.(int), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "HISTORY_SHOWN"),
+		Data:    res,
+	})
+
+}
+
+func (h *TransactionHandler) TransferDetail(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	reference := strings.ToUpper(c.Param("reference"))
+	if _, err := ulid.ParseStrict(reference); err != nil {
+		respondInvalidInput(c, "INPUT_TRANSFER_REF", nil, nil)
+		return
+	}
+
+	res, err := h.TransactionUsecase.GetTransfer(c.Request.Context(), userID.(int), reference)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "TRANSFER_SHOWN"),
+		Data:    res,
+	})
+}
+
+func (h *TransactionHandler) TransactionDetail(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		respondInvalidInput(c, "INPUT_TRANSACTION_ID", nil, nil)
+		return
+	}
+
+	res, err := h.TransactionUsecase.GetTransaction(c.Request.Context(), userID.(int), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "TRANSACTION_SHOWN"),
+		Data:    res,
+	})
+}
+
+func (h *TransactionHandler) Limits(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
 		return
 	}
 
-	res, err := h.TransactionUsecase.GetHistory(c.Request.Context(), userID.(int))
+	res, err := h.TransactionUsecase.GetLimits(c.Request.Context(), userID.(int))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, WebResponse{
-			Status:  "error",
-			Message: err.Error(),
-		})
+		response.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, WebResponse{
+	c.JSON(http.StatusOK, response.WebResponse{
 		Status:  "success",
-		Message: "Riwayat transaksi berhasil ditampilkan",
+		Message: response.Translate(c, "LIMITS_SHOWN"),
 		Data:    res,
 	})
+}
+
+func (h *TransactionHandler) QuoteFee(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req model.FeeQuoteRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondInvalidInput(c, "INPUT_FEE_QUOTE", &req, err)
+		return
+	}
+
+	res, err := h.TransactionUsecase.QuoteFee(c.Request.Context(), userID.(int), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "FEE_QUOTED"),
+		Data:    res,
+	})
+}
+
+func (h *TransactionHandler) Statement(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req model.StatementRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondInvalidInput(c, "INPUT_STATEMENT", &req, err)
+		return
+	}
+
+	st, err := h.TransactionUsecase.GetStatement(c.Request.Context(), userID.(int), req.WalletNumber, req.Month)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("statement-%s-%s", st.WalletNumber, req.Month)
+	var buf bytes.Buffer
+	contentType := "text/csv; charset=utf-8"
+	if req.Format == "pdf" {
+		contentType = "application/pdf"
+		filename += ".pdf"
+		err = writeStatementPDF(&buf, st)
+	} else {
+		filename += ".csv"
+		err = writeStatementCSV(&buf, st)
+	}
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
 
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
 }
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"ewallet-service/internal/model"
+	"ewallet-service/internal/response"
 	"ewallet-service/internal/usecase"
 	"net/http"
 
@@ -21,28 +22,21 @@ func (h *UserHandler) Register(c *gin.Context) {
 
 	// validation input json
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, WebResponse{
-			Status:  "fail",
-			Message: "Input tidak valid",
-			Error:   err.Error(),
-		})
+		respondInvalidInput(c, "INVALID_INPUT", &req, err)
 		return
 	}
 
 	// c.Request.Context() penting untuk meneruskan context (timeout/cancellation)
 	res, err := h.UserUsecase.Register(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusConflict, WebResponse{
-			Status:  "error",
-			Message: err.Error(),
-		})
+		response.Error(c, err)
 		return
 	}
 
 	// response success
-	c.JSON(http.StatusCreated, WebResponse{
+	c.JSON(http.StatusCreated, response.WebResponse{
 		Status:  "success",
-		Message: "User registered successfully",
+		Message: response.Translate(c, "REGISTER_SUCCESS"),
 		Data:    res,
 	})
 }
@@ -51,25 +45,53 @@ func (h *UserHandler) Login(c *gin.Context) {
 	var req model.LoginRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, WebResponse{
-			Status:  "fail",
-			Message: "Input tidak valid",
-			Error:   err.Error(),
-		})
+		respondInvalidInput(c, "INVALID_INPUT", &req, err)
+		return
 	}
 
+	req.ClientIP = c.ClientIP()
+
 	res, err := h.UserUsecase.Login(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, WebResponse{
-			Status:  "fail",
-			Message: err.Error(),
+		response.Error(c, err)
+		return
+	}
+
+	if res.TwoFactorRequired {
+		c.JSON(http.StatusOK, response.WebResponse{
+			Status:  "success",
+			Message: response.Translate(c, "LOGIN_OTP_REQUIRED"),
+			Data:    res,
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, WebResponse{
+	c.JSON(http.StatusOK, response.WebResponse{
 		Status:  "success",
-		Message: "Login berhasil",
+		Message: response.Translate(c, "LOGIN_SUCCESS"),
+		Data:    res,
+	})
+}
+
+func (h *UserHandler) LoginTwoFactor(c *gin.Context) {
+	var req model.LoginTwoFactorRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "INPUT_LOGIN_2FA", &req, err)
+		return
+	}
+
+	req.ClientIP = c.ClientIP()
+
+	res, err := h.UserUsecase.LoginTwoFactor(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "LOGIN_SUCCESS"),
 		Data:    res,
 	})
 }
@@ -77,25 +99,176 @@ func (h *UserHandler) Login(c *gin.Context) {
 func (h *UserHandler) GetBalance(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, WebResponse{
-			Status:  "fail",
-			Message: "Unauthorized",
-		})
+		response.Error(c, response.ErrUnauthorized)
 		return
 	}
 
 	res, err := h.UserUsecase.GetBalance(c.Request.Context(), userID.(int))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, WebResponse{
-			Status:  "error",
-			Message: err.Error(),
-		})
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "BALANCE_SHOWN"),
+		Data:    res,
+	})
+}
+
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	var req model.RefreshTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "INVALID_INPUT", &req, err)
+		return
+	}
+
+	res, err := h.UserUsecase.RefreshToken(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "TOKEN_REFRESHED"),
+		Data:    res,
+	})
+}
+
+func (h *UserHandler) Logout(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	if err := h.UserUsecase.Logout(c.Request.Context(), claims.(*model.AccessClaims)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "LOGOUT_SUCCESS"),
+	})
+}
+
+func (h *UserHandler) SetPIN(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req model.SetPINRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "INPUT_PIN", &req, err)
+		return
+	}
+
+	if err := h.UserUsecase.SetPIN(c.Request.Context(), userID.(int), req); err != nil {
+		response.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, WebResponse{
+	c.JSON(http.StatusCreated, response.WebResponse{
 		Status:  "success",
-		Message: "Data wallet berhasil ditampilkan",
+		Message: response.Translate(c, "PIN_CREATED"),
+	})
+}
+
+func (h *UserHandler) ChangePIN(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req model.ChangePINRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "INPUT_PIN", &req, err)
+		return
+	}
+
+	if err := h.UserUsecase.ChangePIN(c.Request.Context(), userID.(int), req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "PIN_CHANGED"),
+	})
+}
+
+func (h *UserHandler) EnrollTOTP(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	res, err := h.UserUsecase.EnrollTOTP(c.Request.Context(), userID.(int))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "TOTP_ENROLLED"),
 		Data:    res,
 	})
 }
+
+func (h *UserHandler) ConfirmTOTP(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req model.TOTPConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "INPUT_OTP", &req, err)
+		return
+	}
+
+	res, err := h.UserUsecase.ConfirmTOTP(c.Request.Context(), userID.(int), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "TOTP_CONFIRMED"),
+		Data:    res,
+	})
+}
+
+func (h *UserHandler) DisableTOTP(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req model.DisableTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "INVALID_INPUT", &req, err)
+		return
+	}
+
+	if err := h.UserUsecase.DisableTOTP(c.Request.Context(), userID.(int), req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponse{
+		Status:  "success",
+		Message: response.Translate(c, "TOTP_DISABLED"),
+	})
+}
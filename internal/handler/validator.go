@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"ewallet-service/internal/model"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	v.RegisterTagNameFunc(clientFieldName)
+
+	// validator sees Money as its minor units, so `required` means "not zero"
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if m, ok := field.Interface().(model.Money); ok {
+			return m.Amount
+		}
+		return nil
+	}, model.Money{})
+
+	// money_min=10000 -> amount must be at least 10000.00
+	v.RegisterValidation("money_min", func(fl validator.FieldLevel) bool {
+		min, err := model.ParseMoney(fl.Param())
+		if err != nil {
+			return false
+		}
+		return fl.Field().Int() >= min.Amount
+	})
+
+	// wallet_number -> a typo in a wallet number fails its check digit before any lookup
+	v.RegisterValidation("wallet_number", func(fl validator.FieldLevel) bool {
+		return model.ValidWalletNumber(fl.Field().String())
+	})
+}
+
+// clientFieldName is the name the client sends a field by: json, form for query parameters,
+// uri for path parameters.
+func clientFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "uri"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,81 @@
+// Package i18n holds the catalogs of user-facing API messages and picks the language
+// from Accept-Language. Messages are keyed by the same codes the API returns in `code`.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// supported languages; Default is used when the client asks for none of them
+const (
+	ID      = "id"
+	EN      = "en"
+	Default = ID
+)
+
+var catalogs = map[string]map[string]string{
+	ID: messagesID,
+	EN: messagesEN,
+}
+
+// Message returns the text for key in lang, formatted with args. A key missing in lang
+// falls back to the default language, and an unknown key is returned as is.
+func Message(lang, key string, args ...interface{}) string {
+	text, ok := catalogs[lang][key]
+	if !ok {
+		text, ok = catalogs[Default][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// Has reports whether key is in the catalog of lang.
+func Has(lang, key string) bool {
+	_, ok := catalogs[lang][key]
+	return ok
+}
+
+// Keys lists the keys of lang's catalog, sorted.
+func Keys(lang string) []string {
+	keys := make([]string, 0, len(catalogs[lang]))
+	for k := range catalogs[lang] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Negotiate picks the supported language the client prefers most in an Accept-Language
+// header ("en-US,en;q=0.9,id;q=0.8"). Regions are ignored, q=0 excludes a language,
+// and equal weights keep header order.
+func Negotiate(acceptLanguage string) string {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if _, ok := catalogs[lang]; !ok {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
@@ -0,0 +1,43 @@
+package i18n_test
+
+import (
+	"ewallet-service/internal/i18n"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]string{
+		"":                        i18n.ID,
+		"en":                      i18n.EN,
+		"en-US,en;q=0.9":          i18n.EN,
+		"id-ID,id;q=0.9,en;q=0.8": i18n.ID,
+		"fr-FR,en;q=0.5":          i18n.EN,
+		"fr, de":                  i18n.ID,
+		"id;q=0.3, en;q=0.7":      i18n.EN,
+		"en;q=0, id;q=0.1":        i18n.ID,
+		"EN-gb":                   i18n.EN,
+		"en;q=abc":                i18n.ID,
+		"*":                       i18n.ID,
+		"en;q=0.8, id;q=0.8":      i18n.EN,
+	}
+
+	for header, want := range cases {
+		assert.Equal(t, want, i18n.Negotiate(header), header)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	assert.Equal(t, "Saldo tidak mencukupi", i18n.Message(i18n.ID, "INSUFFICIENT_FUNDS"))
+	assert.Equal(t, "Insufficient balance", i18n.Message(i18n.EN, "INSUFFICIENT_FUNDS"))
+	assert.Equal(t, "amount is required", i18n.Message(i18n.EN, "VALIDATION_REQUIRED", "amount"))
+
+	// unknown languages fall back to the default, unknown keys come back as is
+	assert.Equal(t, "Saldo tidak mencukupi", i18n.Message("fr", "INSUFFICIENT_FUNDS"))
+	assert.Equal(t, "NO_SUCH_KEY", i18n.Message(i18n.EN, "NO_SUCH_KEY"))
+}
+
+func TestCatalogsHaveTheSameKeys(t *testing.T) {
+	assert.Equal(t, i18n.Keys(i18n.ID), i18n.Keys(i18n.EN))
+}
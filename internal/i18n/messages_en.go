@@ -0,0 +1,140 @@
+package i18n
+
+var messagesEN = map[string]string{
+	// errors, keyed by the API error code
+	"INTERNAL_ERROR":                 "Something went wrong on our side",
+	"INVALID_INPUT":                  "Invalid input",
+	"INVALID_JSON":                   "Malformed JSON body",
+	"UNAUTHORIZED":                   "Unauthorized",
+	"TOKEN_MISSING":                  "Token missing or malformed",
+	"TOKEN_INVALID":                  "Token is invalid or expired",
+	"TOKEN_REVOKED":                  "Token has been revoked, please log in again",
+	"REFRESH_TOKEN_INVALID":          "Refresh token is invalid or expired",
+	"REFRESH_TOKEN_REUSED":           "Refresh token was already used, the session has been revoked",
+	"INVALID_CREDENTIALS":            "Wrong email or password",
+	"LOGIN_LOCKED":                   "Too many failed login attempts, try again in %s",
+	"RATE_LIMITED":                   "Too many requests, try again in %s",
+	"ACCOUNT_FROZEN":                 "Account is frozen, please contact customer service",
+	"FORBIDDEN":                      "Access denied",
+	"EMAIL_TAKEN":                    "Email is already registered",
+	"EMAIL_ALREADY_VERIFIED":         "Email is already verified",
+	"ACTION_TOKEN_INVALID":           "Token is invalid, expired or already used",
+	"WRONG_PASSWORD":                 "Wrong password",
+	"PIN_NOT_SET":                    "Transaction PIN has not been set",
+	"PIN_ALREADY_SET":                "Transaction PIN is already set, use change PIN instead",
+	"PIN_INVALID":                    "Wrong transaction PIN",
+	"PIN_LOCKED":                     "Transaction PIN is locked after too many attempts, try again later",
+	"TWO_FACTOR_ALREADY_ENABLED":     "2FA is already enabled",
+	"TWO_FACTOR_NOT_ENROLLED":        "2FA is not enrolled, start the enrollment first",
+	"TWO_FACTOR_NOT_ENABLED":         "2FA is not enabled",
+	"OTP_INVALID":                    "OTP code is wrong or already used",
+	"OTP_LOCKED":                     "2FA codes are locked after too many attempts, try again later",
+	"OTP_REQUIRED":                   "A 2FA OTP code is required for a transfer of this amount",
+	"RECOVERY_CODE_INVALID":          "Recovery code is wrong or already used",
+	"INSUFFICIENT_FUNDS":             "Insufficient balance",
+	"SELF_TRANSFER":                  "Source and destination wallet must differ",
+	"RECIPIENT_NOT_FOUND":            "Destination wallet number not found",
+	"WALLET_FROZEN":                  "Wallet is frozen, please contact customer service",
+	"WALLET_CLOSED":                  "Wallet is closed",
+	"WALLET_DEBIT_BLOCKED":           "Wallet is blocked for outgoing transactions",
+	"WALLET_CREDIT_BLOCKED":          "Wallet is blocked for incoming funds",
+	"RECIPIENT_WALLET_UNAVAILABLE":   "Destination wallet cannot receive funds",
+	"TRANSFER_QUOTE_INVALID":         "The transfer quote is invalid, expired or already used",
+	"TRANSFER_QUOTE_FEE_CHANGED":     "The transfer fee went up since the quote, request a new quote",
+	"IDEMPOTENCY_KEY_REUSED":         "Idempotency-Key was already used for a different request",
+	"IDEMPOTENCY_IN_PROGRESS":        "A request with this Idempotency-Key is still being processed",
+	"IDEMPOTENCY_KEY_TOO_LONG":       "Idempotency-Key is too long (max 255 characters)",
+	"PER_TRANSACTION_LIMIT_EXCEEDED": "Amount exceeds the per-transaction limit (max %s)",
+	"DAILY_LIMIT_EXCEEDED":           "Daily limit exceeded, %s left for today",
+	"MONTHLY_LIMIT_EXCEEDED":         "Monthly limit exceeded, %s left this month",
+	"RECIPIENT_LIMIT_EXCEEDED":       "The recipient wallet cannot receive this amount right now",
+	"FEE_EXCEEDS_AMOUNT":             "The top-up fee exceeds the top-up amount",
+	"LIMIT_USAGE_CHANGED":            "Another transaction is being processed, please try again",
+	"POCKET_NOT_FOUND":               "Pocket not found",
+	"INVALID_POCKET_NAME":            "Pocket name must not be empty",
+	"POCKET_NAME_TAKEN":              "Pocket name already in use",
+	"POCKET_LIMIT_REACHED":           "Maximum number of pockets reached (%d)",
+	"USER_NOT_FOUND":                 "User not found",
+	"WALLET_NOT_FOUND":               "Wallet not found",
+	"TRANSFER_NOT_FOUND":             "Transfer not found",
+	"TRANSACTION_NOT_FOUND":          "Transaction not found",
+	"INVALID_CURSOR":                 "Invalid cursor",
+	"INVALID_FILTER":                 "Invalid filter",
+	"INVALID_PERIOD":                 "Invalid statement period",
+	"USER_SEARCH_EMPTY":              "Provide at least one filter: email, name or wallet_number",
+	"SELF_ADMINISTRATION":            "You cannot freeze or change your own account",
+	"STAFF_ACCOUNT":                  "Only admins can freeze or restrict staff accounts",
+	"WALLET_REACTIVATION_ADMIN_ONLY": "Only admins can reactivate a wallet",
+	"WALLET_CLOSE_ADMIN_ONLY":        "Only admins can close a wallet",
+	"WALLET_NOT_EMPTY":               "The wallet still holds a balance, empty it before closing",
+	"WALLET_LOOSEN_ADMIN_ONLY":       "Only admins can lift or swap a wallet restriction",
+	"ACCOUNT_ALREADY_FROZEN":         "Account is already frozen",
+	"ACCOUNT_NOT_FROZEN":             "Account is not frozen",
+
+	// hints for rejected input
+	"INPUT_TOPUP":                 "Invalid input (min: 10000)",
+	"INPUT_LOGIN_2FA":             "Invalid input (provide otp or recovery_code)",
+	"INPUT_PIN":                   "Invalid input (PIN: 6 digits)",
+	"INPUT_OTP":                   "Invalid input (OTP: 6 digits)",
+	"INPUT_NEW_PASSWORD":          "Invalid input (password needs at least 6 characters)",
+	"INPUT_FREEZE":                "Invalid input (reason is required)",
+	"INPUT_ROLE":                  "Invalid input (role: customer, merchant, support or admin)",
+	"INPUT_WALLET_STATUS":         "Invalid input (status: active, frozen, closed, debit_blocked or credit_blocked; reason is required)",
+	"INPUT_STATEMENT":             "Invalid input (month: YYYY-MM, format: csv|pdf, wallet_number: optional pocket number)",
+	"INPUT_FEE_QUOTE":             "Invalid input (type: topup or transfer, amount is required)",
+	"INPUT_POCKET":                "Invalid input (name is required, at most 50 characters)",
+	"INPUT_POCKET_MOVE":           "Invalid input (to_wallet_number, amount and a 6-digit pin are required)",
+	"INPUT_USER_ID":               "Invalid user ID",
+	"INPUT_TRANSACTION_ID":        "Invalid transaction ID",
+	"INPUT_TRANSFER_REF":          "Invalid transfer reference format",
+	"INPUT_WALLET_INQUIRY":        "Invalid wallet number",
+	"VALIDATION_REQUIRED":         "%s is required",
+	"VALIDATION_REQUIRED_WITHOUT": "%s is required when %s is empty",
+	"VALIDATION_MIN":              "%s must be at least %s",
+	"VALIDATION_MIN_LENGTH":       "%s must be at least %s characters",
+	"VALIDATION_MAX":              "%s must be at most %s",
+	"VALIDATION_MAX_LENGTH":       "%s must be at most %s characters",
+	"VALIDATION_LEN":              "%s must be exactly %s characters",
+	"VALIDATION_NUMERIC":          "%s must contain digits only",
+	"VALIDATION_EMAIL":            "%s must be a valid email address",
+	"VALIDATION_ONEOF":            "%s must be one of: %s",
+	"VALIDATION_DATETIME":         "%s must have the format %s",
+	"VALIDATION_WALLET_NUMBER":    "%s is not a valid wallet number",
+	"VALIDATION_INVALID":          "%s is invalid",
+
+	// success messages
+	"REGISTER_SUCCESS":         "User registered successfully",
+	"LOGIN_SUCCESS":            "Login successful",
+	"LOGIN_OTP_REQUIRED":       "Enter the OTP code to finish logging in",
+	"TOKEN_REFRESHED":          "Token refreshed",
+	"LOGOUT_SUCCESS":           "Logged out",
+	"BALANCE_SHOWN":            "Wallet data retrieved",
+	"PIN_CREATED":              "Transaction PIN created",
+	"PIN_CHANGED":              "Transaction PIN changed",
+	"TOTP_ENROLLED":            "Scan provisioning_uri with your authenticator app, then confirm with an OTP code",
+	"TOTP_CONFIRMED":           "2FA enabled, keep the recovery codes somewhere safe (they are shown only once)",
+	"TOTP_DISABLED":            "2FA disabled",
+	"EMAIL_VERIFIED":           "Email verified",
+	"VERIFICATION_RESENT":      "Verification email sent again",
+	"PASSWORD_RESET_SENT":      "If the email is registered, a password reset link has been sent",
+	"PASSWORD_RESET":           "Password changed, please log in again",
+	"TOPUP_SUCCESS":            "Top-up successful",
+	"TRANSFER_SUCCESS":         "Transfer successful",
+	"TRANSFER_QUOTED":          "Check the recipient and the fee, then confirm the transfer with your PIN",
+	"WALLET_INQUIRY_SHOWN":     "Recipient wallet found",
+	"HISTORY_SHOWN":            "Transaction history retrieved",
+	"TRANSFER_SHOWN":           "Transfer details retrieved",
+	"TRANSACTION_SHOWN":        "Transaction details retrieved",
+	"LIMITS_SHOWN":             "Transaction limits retrieved",
+	"FEE_QUOTED":               "Fee quote calculated",
+	"POCKETS_SHOWN":            "Pockets retrieved",
+	"POCKET_CREATED":           "Pocket created",
+	"POCKET_MOVED":             "Funds moved between pockets",
+	"USERS_SHOWN":              "Users retrieved",
+	"USER_SHOWN":               "User details retrieved",
+	"WALLET_SHOWN":             "Wallet details retrieved",
+	"ACCOUNT_FROZEN_SUCCESS":   "Account frozen",
+	"ACCOUNT_UNFROZEN_SUCCESS": "Account unfrozen",
+	"ROLE_CHANGED":             "Role changed",
+	"WALLET_STATUS_CHANGED":    "Wallet status changed",
+}
@@ -0,0 +1,140 @@
+package i18n
+
+var messagesID = map[string]string{
+	// errors, keyed by the API error code
+	"INTERNAL_ERROR":                 "Terjadi kesalahan pada server",
+	"INVALID_INPUT":                  "Input tidak valid",
+	"INVALID_JSON":                   "Format JSON tidak valid",
+	"UNAUTHORIZED":                   "Unauthorized",
+	"TOKEN_MISSING":                  "Token tidak ditemukan atau format salah",
+	"TOKEN_INVALID":                  "Token tidak valid atau kadaluarsa",
+	"TOKEN_REVOKED":                  "Token sudah dicabut, silakan login ulang",
+	"REFRESH_TOKEN_INVALID":          "Refresh token tidak valid atau kadaluarsa",
+	"REFRESH_TOKEN_REUSED":           "Refresh token sudah pernah dipakai, sesi dicabut",
+	"INVALID_CREDENTIALS":            "Email atau password salah",
+	"LOGIN_LOCKED":                   "Terlalu banyak percobaan login gagal, coba lagi dalam %s",
+	"RATE_LIMITED":                   "Terlalu banyak permintaan, coba lagi dalam %s",
+	"ACCOUNT_FROZEN":                 "Akun dibekukan, hubungi customer service",
+	"FORBIDDEN":                      "Akses ditolak",
+	"EMAIL_TAKEN":                    "Email sudah terdaftar",
+	"EMAIL_ALREADY_VERIFIED":         "Email sudah terverifikasi",
+	"ACTION_TOKEN_INVALID":           "Token tidak valid, kadaluarsa atau sudah dipakai",
+	"WRONG_PASSWORD":                 "Password salah",
+	"PIN_NOT_SET":                    "PIN transaksi belum dibuat",
+	"PIN_ALREADY_SET":                "PIN transaksi sudah dibuat, gunakan ubah PIN",
+	"PIN_INVALID":                    "PIN transaksi salah",
+	"PIN_LOCKED":                     "PIN transaksi terkunci karena terlalu banyak percobaan, coba lagi nanti",
+	"TWO_FACTOR_ALREADY_ENABLED":     "2FA sudah aktif",
+	"TWO_FACTOR_NOT_ENROLLED":        "2FA belum didaftarkan, lakukan enroll terlebih dahulu",
+	"TWO_FACTOR_NOT_ENABLED":         "2FA belum aktif",
+	"OTP_INVALID":                    "Kode OTP salah atau sudah dipakai",
+	"OTP_LOCKED":                     "Kode OTP terkunci karena terlalu banyak percobaan, coba lagi nanti",
+	"OTP_REQUIRED":                   "Transfer dengan nominal ini memerlukan kode OTP 2FA",
+	"RECOVERY_CODE_INVALID":          "Recovery code salah atau sudah dipakai",
+	"INSUFFICIENT_FUNDS":             "Saldo tidak mencukupi",
+	"SELF_TRANSFER":                  "Wallet asal dan tujuan tidak boleh sama",
+	"RECIPIENT_NOT_FOUND":            "Nomor wallet tujuan tidak ditemukan",
+	"WALLET_FROZEN":                  "Wallet sedang dibekukan, hubungi customer service",
+	"WALLET_CLOSED":                  "Wallet sudah ditutup",
+	"WALLET_DEBIT_BLOCKED":           "Wallet diblokir untuk transaksi keluar",
+	"WALLET_CREDIT_BLOCKED":          "Wallet diblokir untuk menerima dana",
+	"RECIPIENT_WALLET_UNAVAILABLE":   "Wallet tujuan tidak dapat menerima dana",
+	"TRANSFER_QUOTE_INVALID":         "Quote transfer tidak valid, kadaluarsa atau sudah dipakai",
+	"TRANSFER_QUOTE_FEE_CHANGED":     "Biaya transfer naik sejak quote dibuat, buat quote baru",
+	"IDEMPOTENCY_KEY_REUSED":         "Idempotency-Key sudah dipakai untuk request yang berbeda",
+	"IDEMPOTENCY_IN_PROGRESS":        "Request dengan Idempotency-Key ini masih diproses",
+	"IDEMPOTENCY_KEY_TOO_LONG":       "Idempotency-Key terlalu panjang (maks 255 karakter)",
+	"PER_TRANSACTION_LIMIT_EXCEEDED": "Nominal melebihi batas per transaksi (maks %s)",
+	"DAILY_LIMIT_EXCEEDED":           "Melebihi limit harian, sisa limit hari ini %s",
+	"MONTHLY_LIMIT_EXCEEDED":         "Melebihi limit bulanan, sisa limit bulan ini %s",
+	"RECIPIENT_LIMIT_EXCEEDED":       "Wallet tujuan tidak dapat menerima dana sebesar ini saat ini",
+	"FEE_EXCEEDS_AMOUNT":             "Biaya topup melebihi nominal topup",
+	"LIMIT_USAGE_CHANGED":            "Transaksi lain sedang diproses, silakan coba lagi",
+	"POCKET_NOT_FOUND":               "Pocket tidak ditemukan",
+	"INVALID_POCKET_NAME":            "Nama pocket tidak boleh kosong",
+	"POCKET_NAME_TAKEN":              "Nama pocket sudah dipakai",
+	"POCKET_LIMIT_REACHED":           "Jumlah pocket sudah maksimal (%d)",
+	"USER_NOT_FOUND":                 "User tidak ditemukan",
+	"WALLET_NOT_FOUND":               "Wallet tidak ditemukan",
+	"TRANSFER_NOT_FOUND":             "Transfer tidak ditemukan",
+	"TRANSACTION_NOT_FOUND":          "Transaksi tidak ditemukan",
+	"INVALID_CURSOR":                 "Cursor tidak valid",
+	"INVALID_FILTER":                 "Filter tidak valid",
+	"INVALID_PERIOD":                 "Periode statement tidak valid",
+	"USER_SEARCH_EMPTY":              "Isi minimal satu filter: email, name atau wallet_number",
+	"SELF_ADMINISTRATION":            "Tidak dapat membekukan atau mengubah akun sendiri",
+	"STAFF_ACCOUNT":                  "Hanya admin yang dapat membekukan atau membatasi akun staf",
+	"WALLET_REACTIVATION_ADMIN_ONLY": "Hanya admin yang dapat mengaktifkan kembali wallet",
+	"WALLET_CLOSE_ADMIN_ONLY":        "Hanya admin yang dapat menutup wallet",
+	"WALLET_NOT_EMPTY":               "Wallet masih memiliki saldo, kosongkan sebelum ditutup",
+	"WALLET_LOOSEN_ADMIN_ONLY":       "Hanya admin yang dapat melonggarkan atau mengganti pembatasan wallet",
+	"ACCOUNT_ALREADY_FROZEN":         "Akun sudah dibekukan",
+	"ACCOUNT_NOT_FROZEN":             "Akun tidak sedang dibekukan",
+
+	// hints for rejected input
+	"INPUT_TOPUP":                 "Input tidak valid (min: 10000)",
+	"INPUT_LOGIN_2FA":             "Input tidak valid (isi otp atau recovery_code)",
+	"INPUT_PIN":                   "Input tidak valid (PIN: 6 digit angka)",
+	"INPUT_OTP":                   "Input tidak valid (OTP: 6 digit angka)",
+	"INPUT_NEW_PASSWORD":          "Input tidak valid (password minimal 6 karakter)",
+	"INPUT_FREEZE":                "Input tidak valid (reason wajib diisi)",
+	"INPUT_ROLE":                  "Input tidak valid (role: customer, merchant, support atau admin)",
+	"INPUT_WALLET_STATUS":         "Input tidak valid (status: active, frozen, closed, debit_blocked atau credit_blocked; reason wajib diisi)",
+	"INPUT_STATEMENT":             "Input tidak valid (month: YYYY-MM, format: csv|pdf, wallet_number: nomor pocket, opsional)",
+	"INPUT_FEE_QUOTE":             "Input tidak valid (type: topup atau transfer, amount wajib diisi)",
+	"INPUT_POCKET":                "Input tidak valid (name wajib diisi, maksimal 50 karakter)",
+	"INPUT_POCKET_MOVE":           "Input tidak valid (to_wallet_number, amount dan pin 6 digit wajib diisi)",
+	"INPUT_USER_ID":               "ID user tidak valid",
+	"INPUT_TRANSACTION_ID":        "ID transaksi tidak valid",
+	"INPUT_TRANSFER_REF":          "Format reference transfer tidak valid",
+	"INPUT_WALLET_INQUIRY":        "Nomor wallet tidak valid",
+	"VALIDATION_REQUIRED":         "%s wajib diisi",
+	"VALIDATION_REQUIRED_WITHOUT": "%s wajib diisi jika %s kosong",
+	"VALIDATION_MIN":              "%s minimal %s",
+	"VALIDATION_MIN_LENGTH":       "%s minimal %s karakter",
+	"VALIDATION_MAX":              "%s maksimal %s",
+	"VALIDATION_MAX_LENGTH":       "%s maksimal %s karakter",
+	"VALIDATION_LEN":              "%s harus %s karakter",
+	"VALIDATION_NUMERIC":          "%s harus berupa angka",
+	"VALIDATION_EMAIL":            "%s harus berupa alamat email yang valid",
+	"VALIDATION_ONEOF":            "%s harus salah satu dari: %s",
+	"VALIDATION_DATETIME":         "%s harus berformat %s",
+	"VALIDATION_WALLET_NUMBER":    "%s bukan nomor wallet yang valid",
+	"VALIDATION_INVALID":          "%s tidak valid",
+
+	// success messages
+	"REGISTER_SUCCESS":         "Registrasi berhasil",
+	"LOGIN_SUCCESS":            "Login berhasil",
+	"LOGIN_OTP_REQUIRED":       "Masukkan kode OTP untuk menyelesaikan login",
+	"TOKEN_REFRESHED":          "Token berhasil diperbarui",
+	"LOGOUT_SUCCESS":           "Logout berhasil",
+	"BALANCE_SHOWN":            "Data wallet berhasil ditampilkan",
+	"PIN_CREATED":              "PIN transaksi berhasil dibuat",
+	"PIN_CHANGED":              "PIN transaksi berhasil diubah",
+	"TOTP_ENROLLED":            "Scan provisioning_uri dengan aplikasi authenticator, lalu konfirmasi dengan kode OTP",
+	"TOTP_CONFIRMED":           "2FA aktif, simpan recovery code di tempat yang aman (hanya ditampilkan sekali)",
+	"TOTP_DISABLED":            "2FA dinonaktifkan",
+	"EMAIL_VERIFIED":           "Email berhasil diverifikasi",
+	"VERIFICATION_RESENT":      "Email verifikasi dikirim ulang",
+	"PASSWORD_RESET_SENT":      "Jika email terdaftar, link reset password sudah dikirim",
+	"PASSWORD_RESET":           "Password berhasil diganti, silakan login ulang",
+	"TOPUP_SUCCESS":            "Topup berhasil",
+	"TRANSFER_SUCCESS":         "Transfer berhasil",
+	"TRANSFER_QUOTED":          "Periksa penerima dan biaya, lalu konfirmasi transfer dengan PIN",
+	"WALLET_INQUIRY_SHOWN":     "Data wallet tujuan berhasil ditemukan",
+	"HISTORY_SHOWN":            "Riwayat transaksi berhasil ditampilkan",
+	"TRANSFER_SHOWN":           "Detail transfer berhasil ditampilkan",
+	"TRANSACTION_SHOWN":        "Detail transaksi berhasil ditampilkan",
+	"LIMITS_SHOWN":             "Limit transaksi berhasil ditampilkan",
+	"FEE_QUOTED":               "Estimasi biaya berhasil dihitung",
+	"POCKETS_SHOWN":            "Daftar pocket berhasil ditampilkan",
+	"POCKET_CREATED":           "Pocket berhasil dibuat",
+	"POCKET_MOVED":             "Dana berhasil dipindahkan antar pocket",
+	"USERS_SHOWN":              "Daftar user berhasil ditampilkan",
+	"USER_SHOWN":               "Detail user berhasil ditampilkan",
+	"WALLET_SHOWN":             "Detail wallet berhasil ditampilkan",
+	"ACCOUNT_FROZEN_SUCCESS":   "Akun berhasil dibekukan",
+	"ACCOUNT_UNFROZEN_SUCCESS": "Akun berhasil diaktifkan kembali",
+	"ROLE_CHANGED":             "Role berhasil diubah",
+	"WALLET_STATUS_CHANGED":    "Status wallet berhasil diubah",
+}
@@ -0,0 +1,35 @@
+package jwtkeys
+
+import (
+	"errors"
+	"log"
+	"os"
+)
+
+// FromEnv loads the key set configured by
+//
+//	JWT_KEYS_DIR     directory of <kid>.pem files (see LoadDir)
+//	JWT_SIGNING_KID  the kid that signs new tokens
+//
+// Without JWT_KEYS_DIR a throwaway Ed25519 key is generated: fine for local runs, but every
+// restart logs everybody out and several instances would not accept each other's tokens.
+//
+// Old HS256 access tokens are not accepted: they expire within minutes and refresh tokens are
+// not JWTs, so their holders just refresh.
+func FromEnv() (*KeySet, error) {
+	dir := os.Getenv("JWT_KEYS_DIR")
+	if dir == "" {
+		log.Println("⚠️  JWT_KEYS_DIR kosong, memakai kunci JWT sementara (token hilang saat restart)")
+		key, err := GenerateEd25519("ephemeral")
+		if err != nil {
+			return nil, err
+		}
+		return New(key)
+	}
+
+	kid := os.Getenv("JWT_SIGNING_KID")
+	if kid == "" {
+		return nil, errors.New("JWT_SIGNING_KID wajib diisi bersama JWT_KEYS_DIR")
+	}
+	return LoadDir(dir, kid)
+}
@@ -0,0 +1,234 @@
+// Package jwtkeys holds the asymmetric keys that sign and verify our JWTs.
+//
+// One key signs, any number of others still verify, so a new key can be introduced (and
+// published in the JWKS) before it signs, and an old one kept until its tokens expired.
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+
+	minRSABits = 2048
+)
+
+var ErrUnknownKey = errors.New("kid tidak dikenal")
+
+// Key is one signing key pair, or only the public half of a retired key.
+type Key struct {
+	ID        string
+	Algorithm string
+	Public    crypto.PublicKey
+	private   crypto.Signer
+}
+
+func (k *Key) CanSign() bool {
+	return k.private != nil
+}
+
+func (k *Key) method() jwt.SigningMethod {
+	if k.Algorithm == AlgEdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+// New builds a key set. signing must hold a private key; verifyOnly keys are accepted on
+// tokens and published in the JWKS but never sign.
+func New(signing *Key, verifyOnly ...*Key) (*KeySet, error) {
+	if signing == nil || !signing.CanSign() {
+		return nil, errors.New("kunci penandatangan JWT harus punya private key")
+	}
+
+	ks := &KeySet{signing: signing, keys: map[string]*Key{signing.ID: signing}}
+	for _, k := range verifyOnly {
+		if _, dup := ks.keys[k.ID]; dup {
+			return nil, fmt.Errorf("kid %s dipakai lebih dari sekali", k.ID)
+		}
+		ks.keys[k.ID] = k
+	}
+	return ks, nil
+}
+
+func (ks *KeySet) SigningKeyID() string {
+	return ks.signing.ID
+}
+
+// Sign signs claims with the current signing key and puts its id in the kid header.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.method(), claims)
+	token.Header["kid"] = ks.signing.ID
+	return token.SignedString(ks.signing.private)
+}
+
+// Parse verifies a token with the key named by its kid and fills claims; tokens without a kid,
+// such as the old HS256 ones, are refused. The algorithm comes from our key, never from the
+// token header alone.
+// opts add checks on the claims, e.g. jwt.WithAudience.
+func (ks *KeySet) Parse(tokenString string, claims jwt.Claims, opts ...jwt.ParserOption) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := ks.keys[kid]
+		if !ok {
+			return nil, ErrUnknownKey
+		}
+		if token.Method.Alg() != key.Algorithm {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return key.Public, nil
+	}, opts...)
+}
+
+// Keys returns every verification key, sorted by id.
+func (ks *KeySet) Keys() []*Key {
+	keys := make([]*Key, 0, len(ks.keys))
+	for _, k := range ks.keys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys
+}
+
+// GenerateEd25519 creates a new EdDSA key.
+func GenerateEd25519(kid string) (*Key, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{ID: kid, Algorithm: AlgEdDSA, Public: pub, private: priv}, nil
+}
+
+// GenerateRSA creates a new RS256 key.
+func GenerateRSA(kid string, bits int) (*Key, error) {
+	if bits < minRSABits {
+		return nil, fmt.Errorf("kunci RSA minimal %d bit", minRSABits)
+	}
+	priv, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{ID: kid, Algorithm: AlgRS256, Public: &priv.PublicKey, private: priv}, nil
+}
+
+// MarshalPrivatePEM encodes the private key as PKCS#8 PEM.
+func (k *Key) MarshalPrivatePEM() ([]byte, error) {
+	if !k.CanSign() {
+		return nil, errors.New("kunci tidak punya private key")
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(k.private)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// MarshalPublicPEM encodes the public key as PKIX PEM, what stays on disk after retiring a key.
+func (k *Key) MarshalPublicPEM() ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(k.Public)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// ParsePEM reads a PKCS#8 or PKCS#1 private key, or a PKIX public key (verify only).
+func ParsePEM(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("kunci %s: bukan file PEM", kid)
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("kunci %s: %w", kid, err)
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("kunci %s: tipe kunci tidak didukung", kid)
+		}
+		return newKey(kid, signer.Public(), signer)
+	case "RSA PRIVATE KEY":
+		priv, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("kunci %s: %w", kid, err)
+		}
+		return newKey(kid, &priv.PublicKey, priv)
+	case "PUBLIC KEY":
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("kunci %s: %w", kid, err)
+		}
+		return newKey(kid, pub, nil)
+	default:
+		return nil, fmt.Errorf("kunci %s: blok PEM %q tidak didukung", kid, block.Type)
+	}
+}
+
+func newKey(kid string, pub crypto.PublicKey, priv crypto.Signer) (*Key, error) {
+	switch p := pub.(type) {
+	case ed25519.PublicKey:
+		return &Key{ID: kid, Algorithm: AlgEdDSA, Public: p, private: priv}, nil
+	case *rsa.PublicKey:
+		if p.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("kunci %s: RSA minimal %d bit", kid, minRSABits)
+		}
+		return &Key{ID: kid, Algorithm: AlgRS256, Public: p, private: priv}, nil
+	default:
+		return nil, fmt.Errorf("kunci %s: hanya RSA dan Ed25519 yang didukung", kid)
+	}
+}
+
+// LoadDir loads every <kid>.pem in dir. The key named signingKID signs, the rest only verify;
+// a retired key can be reduced to its public half (<kid>.pem holding a PUBLIC KEY block).
+func LoadDir(dir, signingKID string) (*KeySet, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	var signing *Key
+	var others []*Key
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		key, err := ParsePEM(strings.TrimSuffix(filepath.Base(file), ".pem"), data)
+		if err != nil {
+			return nil, err
+		}
+		if key.ID == signingKID {
+			signing = key
+		} else {
+			others = append(others, key)
+		}
+	}
+
+	if signing == nil {
+		return nil, fmt.Errorf("kunci penandatangan %q tidak ada di %s", signingKID, dir)
+	}
+	return New(signing, others...)
+}
@@ -0,0 +1,127 @@
+package jwtkeys_test
+
+import (
+	"ewallet-service/internal/jwtkeys"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func newClaims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{Subject: "1", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}
+}
+
+func TestSignAndParse_EdDSA(t *testing.T) {
+	// arrange
+	key, _ := jwtkeys.GenerateEd25519("2026-10")
+	ks, _ := jwtkeys.New(key)
+
+	// act
+	token, err := ks.Sign(newClaims())
+	assert.NoError(t, err)
+
+	var claims jwt.RegisteredClaims
+	parsed, err := ks.Parse(token, &claims)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-10", parsed.Header["kid"])
+	assert.Equal(t, "EdDSA", parsed.Header["alg"])
+	assert.Equal(t, "1", claims.Subject)
+}
+
+func TestRotation_OldKeyStillVerifies(t *testing.T) {
+	// arrange: tokens signed before the rotation
+	oldKey, _ := jwtkeys.GenerateEd25519("old")
+	before, _ := jwtkeys.New(oldKey)
+	oldToken, _ := before.Sign(newClaims())
+
+	newKey, _ := jwtkeys.GenerateRSA("new", 2048)
+	after, _ := jwtkeys.New(newKey, oldKey)
+
+	// act
+	_, errOld := after.Parse(oldToken, &jwt.RegisteredClaims{})
+	newToken, _ := after.Sign(newClaims())
+	parsedNew, errNew := after.Parse(newToken, &jwt.RegisteredClaims{})
+
+	// assert
+	assert.NoError(t, errOld)
+	assert.NoError(t, errNew)
+	assert.Equal(t, "RS256", parsedNew.Header["alg"])
+	assert.Len(t, after.JWKS().Keys, 2)
+}
+
+func TestParse_RejectsUnknownKidAndLegacyHS256(t *testing.T) {
+	// arrange
+	key, _ := jwtkeys.GenerateEd25519("current")
+	ks, _ := jwtkeys.New(key)
+
+	other, _ := jwtkeys.GenerateEd25519("stranger")
+	foreign, _ := jwtkeys.New(other)
+	foreignToken, _ := foreign.Sign(newClaims())
+
+	hsToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims()).SignedString([]byte("old-secret"))
+
+	// act
+	_, errForeign := ks.Parse(foreignToken, &jwt.RegisteredClaims{})
+	_, errLegacy := ks.Parse(hsToken, &jwt.RegisteredClaims{})
+
+	// assert
+	assert.ErrorIs(t, errForeign, jwtkeys.ErrUnknownKey)
+	assert.ErrorIs(t, errLegacy, jwtkeys.ErrUnknownKey)
+}
+
+func TestParse_RejectsAlgorithmSwitch(t *testing.T) {
+	// arrange: an HS256 token claiming the kid of our RSA key
+	key, _ := jwtkeys.GenerateRSA("rsa", 2048)
+	ks, _ := jwtkeys.New(key)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims())
+	token.Header["kid"] = "rsa"
+	forged, _ := token.SignedString([]byte("anything"))
+
+	// act
+	_, err := ks.Parse(forged, &jwt.RegisteredClaims{})
+
+	// assert
+	assert.Error(t, err)
+}
+
+func TestLoadDir_PublicOnlyKeyVerifies(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	current, _ := jwtkeys.GenerateEd25519("current")
+	currentPEM, _ := current.MarshalPrivatePEM()
+	os.WriteFile(filepath.Join(dir, "current.pem"), currentPEM, 0o600)
+
+	retired, _ := jwtkeys.GenerateEd25519("retired")
+	retiredSet, _ := jwtkeys.New(retired)
+	retiredToken, _ := retiredSet.Sign(newClaims())
+	retiredPEM, _ := retired.MarshalPublicPEM()
+	os.WriteFile(filepath.Join(dir, "retired.pem"), retiredPEM, 0o600)
+
+	// act
+	ks, err := jwtkeys.LoadDir(dir, "current")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "current", ks.SigningKeyID())
+	_, err = ks.Parse(retiredToken, &jwt.RegisteredClaims{})
+	assert.NoError(t, err)
+}
+
+func TestJWKS_Ed25519(t *testing.T) {
+	key, _ := jwtkeys.GenerateEd25519("k1")
+	ks, _ := jwtkeys.New(key)
+
+	set := ks.JWKS()
+
+	assert.Len(t, set.Keys, 1)
+	assert.Equal(t, "OKP", set.Keys[0].KeyType)
+	assert.Equal(t, "Ed25519", set.Keys[0].Curve)
+	assert.Len(t, set.Keys[0].X, 43)
+}
@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// FileMailer writes each message as an .eml file, handy in development and for tests
+// that need to read the link out of a sent email.
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{Dir: dir, From: from}
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	if err := validHeader(msg.To, msg.Subject); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.Dir, 0o700); err != nil {
+		return fmt.Errorf("Gagal membuat folder email: %w", err)
+	}
+
+	// ULID names sort by send time
+	path := filepath.Join(m.Dir, ulid.Make().String()+".eml")
+	return os.WriteFile(path, compose(m.From, msg, time.Now()), 0o600)
+}
+
+// LogMailer prints messages to the standard logger instead of sending them.
+type LogMailer struct {
+	From string
+}
+
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{From: from}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	if err := validHeader(msg.To, msg.Subject); err != nil {
+		return err
+	}
+	log.Printf("📧 email to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,44 @@
+package mailer_test
+
+import (
+	"context"
+	"ewallet-service/internal/mailer"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileMailer_WritesEML(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	m := mailer.NewFileMailer(dir, "no-reply@ewallet.local")
+
+	// act
+	err := m.Send(context.Background(), mailer.Message{
+		To:      "budi@example.com",
+		Subject: "Verifikasi email",
+		Body:    "Halo\nklik link ini",
+	})
+
+	// assert
+	assert.NoError(t, err)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	assert.Len(t, files, 1)
+
+	raw, _ := os.ReadFile(files[0])
+	content := string(raw)
+	assert.True(t, strings.HasPrefix(content, "From: no-reply@ewallet.local\r\nTo: budi@example.com\r\nSubject: Verifikasi email\r\n"))
+	assert.True(t, strings.HasSuffix(content, "\r\n\r\nHalo\r\nklik link ini"))
+}
+
+func TestFileMailer_RejectsHeaderInjection(t *testing.T) {
+	m := mailer.NewFileMailer(t.TempDir(), "no-reply@ewallet.local")
+
+	err := m.Send(context.Background(), mailer.Message{To: "budi@example.com\r\nBcc: evil@example.com", Subject: "x"})
+
+	assert.Error(t, err)
+}
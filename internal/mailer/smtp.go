@@ -0,0 +1,43 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+type SMTPMailer struct {
+	Addr string
+	From string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer sends through host:port. Without username no AUTH is used, which is what
+// local stand-in servers expect; net/smtp only sends credentials over TLS or to localhost.
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	m := &SMTPMailer{Addr: net.JoinHostPort(host, port), From: from}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := validHeader(msg.To, msg.Subject); err != nil {
+		return err
+	}
+
+	// smtp.SendMail has no context, so it runs aside and the caller stops waiting on cancel
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(m.Addr, m.auth, m.From, []string{msg.To}, compose(m.From, msg, time.Now()))
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,55 +1,33 @@
 package middleware
 
 import (
-	"ewallet-service/internal/handler"
-	"net/http"
-	"os"
+	"ewallet-service/internal/response"
+	"ewallet-service/internal/usecase"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+func AuthMiddleware(auth *usecase.AuthUsecase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if !strings.HasPrefix(authHeader, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, handler.WebResponse{
-				Status:  "fail",
-				Message: "Token tidak ditemukan atau format salah",
-			})
+			response.Error(c, response.ErrMissingToken)
 			return
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		secretKey := []byte(os.Getenv("JWT_SECRET"))
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, gin.Error{Err: jwt.ErrSignatureInvalid}
-			}
-			return secretKey, nil
-		})
-
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, handler.WebResponse{
-				Status:  "fail",
-				Message: "Token tidak valid atau kadaluarsa",
-			})
+		// signature, expiry and revocation list (jti) are checked here
+		claims, err := auth.ParseAccessToken(c.Request.Context(), tokenString)
+		if err != nil {
+			response.Error(c, err)
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			userID := int(claims["user_id"].(float64))
-
-			c.Set("userID", userID)
-		} else {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, handler.WebResponse{
-				Status:  "fail",
-				Message: "Gagal memproses token claims",
-			})
-			return 
-		}
+		c.Set("userID", claims.UserID)
+		c.Set("role", claims.Role)
+		c.Set("claims", claims)
 
 		c.Next()
 	}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"ewallet-service/internal/response"
+	"ewallet-service/internal/usecase"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// bodyRecorder copies everything the handler writes so it can be stored for replays.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// IdempotencyMiddleware honours the Idempotency-Key header: the first request runs normally
+// and its response is stored, a retry with the same key and body gets the stored response back,
+// and the same key with a different body is rejected with 422. Bodies are compared as JSON, so
+// key order and whitespace do not matter. Requests without the header are passed through untouched. Must run after AuthMiddleware (keys are scoped per user).
+func IdempotencyMiddleware(u *usecase.IdempotencyUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			response.Error(c, response.ErrIdempotencyKeyTooLong)
+			return
+		}
+
+		userID, exists := c.Get("userID")
+		if !exists {
+			response.Error(c, response.ErrUnauthorized)
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.Error(c, response.ErrUnreadableBody)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		record, err := u.Begin(ctx, userID.(int), c.FullPath(), key, body)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+
+		// replay the original response, money is not moved again
+		if record.Completed() {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(record.ResponseStatus, "application/json; charset=utf-8", record.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+
+		// use a fresh context: the request one may already be cancelled by a client timeout,
+		// which is exactly the case we must still record
+		finishCtx := context.WithoutCancel(ctx)
+
+		// a panicking handler would leave the placeholder behind and every retry would get 409;
+		// release the key like any other server error and let gin's Recovery answer the request
+		defer func() {
+			if p := recover(); p != nil {
+				if err := u.Finish(finishCtx, record, http.StatusInternalServerError, nil); err != nil {
+					c.Error(err)
+				}
+				panic(p)
+			}
+		}()
+
+		c.Next()
+
+		if err := u.Finish(finishCtx, record, recorder.Status(), recorder.body.Bytes()); err != nil {
+			c.Error(err)
+		}
+	}
+}
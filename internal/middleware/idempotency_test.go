@@ -0,0 +1,55 @@
+package middleware_test
+
+import (
+	"ewallet-service/internal/middleware"
+	"ewallet-service/internal/repository"
+	"ewallet-service/internal/repository/mocks"
+	"ewallet-service/internal/response"
+	"ewallet-service/internal/usecase"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdempotency_RetryAfterUsageChangedSucceeds(t *testing.T) {
+	// arrange: the first transfer loses the race for the limit usage, the retry goes through
+	gin.SetMode(gin.TestMode)
+	mockRepo := new(mocks.IdempotencyRepositoryMock)
+	mockRepo.On("Reserve", mock.Anything, mock.AnythingOfType("*model.IdempotencyRecord")).Return(true, nil)
+	mockRepo.On("Release", mock.Anything, mock.Anything).Return(nil).Once()
+	mockRepo.On("Complete", mock.Anything, mock.Anything, http.StatusOK, mock.Anything).Return(nil).Once()
+
+	attempts := 0
+	r := gin.New()
+	r.POST("/api/v1/transfer", func(c *gin.Context) { c.Set("userID", 1) }, middleware.IdempotencyMiddleware(usecase.NewIdempotencyUsecase(mockRepo)), func(c *gin.Context) {
+		attempts++
+		if attempts == 1 {
+			response.Error(c, repository.ErrLimitUsageChanged)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success"})
+	})
+	send := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/transfer", strings.NewReader(`{"amount":"50000"}`))
+		req.Header.Set("Idempotency-Key", "key-1")
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// act
+	first := send()
+	second := send()
+
+	// assert
+	assert.Equal(t, http.StatusServiceUnavailable, first.Code)
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Empty(t, second.Header().Get("Idempotent-Replayed"))
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, http.StatusServiceUnavailable, mock.Anything)
+}
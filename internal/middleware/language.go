@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"ewallet-service/internal/i18n"
+	"ewallet-service/internal/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Language picks the response language from Accept-Language (Indonesian by default)
+// for every handler and middleware after it.
+func Language() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+		c.Set(response.LangKey, lang)
+		c.Header("Content-Language", lang)
+		c.Header("Vary", "Accept-Language")
+
+		c.Next()
+	}
+}
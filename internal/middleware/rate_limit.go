@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"ewallet-service/internal/response"
+	"ewallet-service/internal/usecase"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateKey picks who a rate limit applies to.
+type RateKey func(c *gin.Context) string
+
+// PerUser limits each logged-in user (needs AuthMiddleware in front), falling back to the client IP.
+func PerUser(c *gin.Context) string {
+	if userID, ok := c.Get("userID"); ok {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return PerIP(c)
+}
+
+// PerIP limits each client IP, however many accounts are used from it.
+func PerIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimit allows limit requests per window for every key on the routes it guards and answers
+// 429 with Retry-After beyond that. Routes sharing a name share the budget.
+func RateLimit(u *usecase.RateLimitUsecase, name string, limit int, window time.Duration, key RateKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := u.Allow(c.Request.Context(), name+":"+key(c), limit, window); err != nil {
+			response.Error(c, err)
+			return
+		}
+		c.Next()
+	}
+}
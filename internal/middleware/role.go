@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"ewallet-service/internal/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole lets the request through only when the token's role is one of roles.
+// Must run after AuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	return func(c *gin.Context) {
+		if !allowed[c.GetString("role")] {
+			response.Error(c, response.ErrForbidden)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// purposes of single-use action tokens sent by email
+const (
+	TokenPurposeVerifyEmail   = "verify_email"
+	TokenPurposeResetPassword = "reset_password"
+)
+
+// ActionToken is the stored half of a signed email token: the signature proves where it
+// came from, the row (by JTI) makes it single-use.
+type ActionToken struct {
+	JTI       string
+	UserID    int
+	Purpose   string
+	ExpiresAt time.Time
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
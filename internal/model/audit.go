@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// audit events
+const (
+	AuditLoginLocked     = "LOGIN_LOCKED"
+	AuditLoginUnlocked   = "LOGIN_UNLOCKED"
+	AuditAccountFrozen   = "ACCOUNT_FROZEN"
+	AuditAccountUnfrozen = "ACCOUNT_UNFROZEN"
+	AuditRoleChanged     = "ROLE_CHANGED"
+	AuditWalletStatus    = "WALLET_STATUS_CHANGED"
+)
+
+// AuditEntry is an append-only record of a security relevant event.
+// Actor is who caused it ("system" for automatic actions), Subject what it is about.
+type AuditEntry struct {
+	ID        int       `json:"id"`
+	Event     string    `json:"event"`
+	Actor     string    `json:"actor"`
+	Subject   string    `json:"subject"`
+	Detail    string    `json:"detail"`
+	IP        string    `json:"ip,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,167 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// transaction kinds a fee can be charged on
+const (
+	FeeKindTopUp    = "topup"
+	FeeKindTransfer = "transfer"
+)
+
+// fee rule types
+const (
+	FeeFlat       = "flat"       // always Flat
+	FeePercentage = "percentage" // PercentBps of the amount
+	FeeTiered     = "tiered"     // Flat + PercentBps of the first tier the amount fits in
+)
+
+var ErrInvalidFeeRule = errors.New("Aturan biaya tidak valid")
+
+// FeeTier is one bracket of a tiered rule; a zero UpTo is open-ended and must come last.
+type FeeTier struct {
+	UpTo       Money `json:"up_to"`
+	Flat       Money `json:"flat"`
+	PercentBps int64 `json:"percent_bps"`
+}
+
+// FeeRule computes the fee of one transaction. Percentages are in basis points
+// (150 = 1.5%) so no float is involved. Min and Max clamp the result when not zero,
+// and the first FreePerMonth transactions of a calendar month cost nothing.
+type FeeRule struct {
+	Type         string    `json:"type"`
+	Flat         Money     `json:"flat"`
+	PercentBps   int64     `json:"percent_bps"`
+	Tiers        []FeeTier `json:"tiers"`
+	Min          Money     `json:"min"`
+	Max          Money     `json:"max"`
+	FreePerMonth int       `json:"free_per_month"`
+}
+
+// Fee is the fee for amount, given how many transactions of this kind the user already made this month.
+func (r FeeRule) Fee(amount Money, usedThisMonth int) Money {
+	if usedThisMonth < r.FreePerMonth {
+		return NewMoney(0)
+	}
+
+	fee := NewMoney(0)
+	switch r.Type {
+	case FeeFlat:
+		fee = r.Flat
+	case FeePercentage:
+		fee = percentOf(amount, r.PercentBps)
+	case FeeTiered:
+		for _, t := range r.Tiers {
+			if t.UpTo.IsZero() || amount.Cmp(t.UpTo) <= 0 {
+				fee = t.Flat.Add(percentOf(amount, t.PercentBps))
+				break
+			}
+		}
+	}
+
+	if !r.Min.IsZero() && fee.LessThan(r.Min) {
+		fee = r.Min
+	}
+	if !r.Max.IsZero() && r.Max.LessThan(fee) {
+		fee = r.Max
+	}
+	return MoneyFromMinor(fee.Amount)
+}
+
+// percentOf rounds half up to the minor unit; splitting the amount keeps amount*bps from overflowing.
+func percentOf(amount Money, bps int64) Money {
+	const whole = 10000
+	minor := amount.Amount/whole*bps + (amount.Amount%whole*bps+whole/2)/whole
+	return MoneyFromMinor(minor)
+}
+
+func (r FeeRule) Validate() error {
+	if r.Flat.IsNegative() || r.Min.IsNegative() || r.Max.IsNegative() || r.PercentBps < 0 || r.FreePerMonth < 0 {
+		return fmt.Errorf("%w: nilai tidak boleh negatif", ErrInvalidFeeRule)
+	}
+	if !r.Min.IsZero() && !r.Max.IsZero() && r.Max.LessThan(r.Min) {
+		return fmt.Errorf("%w: max lebih kecil dari min", ErrInvalidFeeRule)
+	}
+
+	switch r.Type {
+	case FeeFlat, FeePercentage:
+		return nil
+	case FeeTiered:
+		if len(r.Tiers) == 0 {
+			return fmt.Errorf("%w: tiers kosong", ErrInvalidFeeRule)
+		}
+		for i, t := range r.Tiers {
+			if t.Flat.IsNegative() || t.PercentBps < 0 {
+				return fmt.Errorf("%w: nilai tier tidak boleh negatif", ErrInvalidFeeRule)
+			}
+			if t.UpTo.IsZero() && i != len(r.Tiers)-1 {
+				return fmt.Errorf("%w: tier tanpa up_to harus yang terakhir", ErrInvalidFeeRule)
+			}
+			if i > 0 && !t.UpTo.IsZero() && !r.Tiers[i-1].UpTo.LessThan(t.UpTo) {
+				return fmt.Errorf("%w: up_to harus urut naik", ErrInvalidFeeRule)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: type %q tidak dikenal", ErrInvalidFeeRule, r.Type)
+}
+
+// FeeSchedule holds the rules per transaction kind and limit tier:
+// {"transfer": {"unverified": {...}, "verified": {...}}, "topup": {...}}
+type FeeSchedule map[string]map[string]FeeRule
+
+// Rule falls back to the unverified tier like the limits do; without any rule there is no fee.
+func (s FeeSchedule) Rule(kind, tier string) (FeeRule, bool) {
+	rules := s[kind]
+	if rule, ok := rules[tier]; ok {
+		return rule, true
+	}
+	rule, ok := rules[TierUnverified]
+	return rule, ok
+}
+
+func (s FeeSchedule) Validate() error {
+	for kind, rules := range s {
+		if kind != FeeKindTopUp && kind != FeeKindTransfer {
+			return fmt.Errorf("%w: jenis transaksi %q tidak dikenal", ErrInvalidFeeRule, kind)
+		}
+		for tier, rule := range rules {
+			if err := rule.Validate(); err != nil {
+				return fmt.Errorf("%s/%s: %w", kind, tier, err)
+			}
+		}
+	}
+	return nil
+}
+
+// DefaultFeeSchedule is used unless FEE_SCHEDULE_FILE says otherwise: top-ups are free,
+// transfers cost 2500 and verified users get their first 5 transfers of a month free.
+func DefaultFeeSchedule() FeeSchedule {
+	return FeeSchedule{
+		FeeKindTopUp: {
+			TierUnverified: {Type: FeeFlat},
+		},
+		FeeKindTransfer: {
+			TierUnverified: {Type: FeeFlat, Flat: NewMoney(2500)},
+			TierVerified:   {Type: FeeFlat, Flat: NewMoney(2500), FreePerMonth: 5},
+		},
+	}
+}
+
+// FeeQuoteRequest is bound from the query string of GET /fees/quote.
+type FeeQuoteRequest struct {
+	Type   string `form:"type" binding:"required,oneof=topup transfer"`
+	Amount Money  `form:"amount" binding:"required,money_min=1"`
+}
+
+// FeeQuote previews a transaction: for a transfer Total is what leaves the wallet,
+// for a top-up it is what ends up in it.
+type FeeQuote struct {
+	Type          string `json:"type"`
+	Amount        Money  `json:"amount"`
+	Fee           Money  `json:"fee"`
+	Total         Money  `json:"total"`
+	FreeRemaining *int   `json:"free_remaining,omitempty"`
+}
@@ -0,0 +1,77 @@
+package model_test
+
+import (
+	"ewallet-service/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeRule_Fee(t *testing.T) {
+	tiered := model.FeeRule{
+		Type: model.FeeTiered,
+		Tiers: []model.FeeTier{
+			{UpTo: model.NewMoney(100000), Flat: model.NewMoney(1000)},
+			{UpTo: model.NewMoney(1000000), PercentBps: 50},
+			{Flat: model.NewMoney(2000), PercentBps: 10},
+		},
+	}
+
+	cases := []struct {
+		name   string
+		rule   model.FeeRule
+		amount model.Money
+		used   int
+		want   model.Money
+	}{
+		{"flat", model.FeeRule{Type: model.FeeFlat, Flat: model.NewMoney(2500)}, model.NewMoney(50000), 0, model.NewMoney(2500)},
+		{"percentage rounds half up", model.FeeRule{Type: model.FeePercentage, PercentBps: 150}, model.MoneyFromMinor(3333), 0, model.MoneyFromMinor(50)},
+		{"percentage of large amount", model.FeeRule{Type: model.FeePercentage, PercentBps: 25}, model.NewMoney(123456789), 0, model.MoneyFromMinor(30864197)},
+		{"min", model.FeeRule{Type: model.FeePercentage, PercentBps: 10, Min: model.NewMoney(1000)}, model.NewMoney(50000), 0, model.NewMoney(1000)},
+		{"max", model.FeeRule{Type: model.FeePercentage, PercentBps: 100, Max: model.NewMoney(5000)}, model.NewMoney(1000000), 0, model.NewMoney(5000)},
+		{"tier boundary is inclusive", tiered, model.NewMoney(100000), 0, model.NewMoney(1000)},
+		{"second tier", tiered, model.NewMoney(200000), 0, model.NewMoney(1000)},
+		{"open-ended tier", tiered, model.NewMoney(5000000), 0, model.NewMoney(7000)},
+		{"free quota left", model.FeeRule{Type: model.FeeFlat, Flat: model.NewMoney(2500), FreePerMonth: 5}, model.NewMoney(50000), 4, model.NewMoney(0)},
+		{"free quota used up", model.FeeRule{Type: model.FeeFlat, Flat: model.NewMoney(2500), FreePerMonth: 5}, model.NewMoney(50000), 5, model.NewMoney(2500)},
+		{"no fee", model.FeeRule{Type: model.FeeFlat}, model.NewMoney(50000), 0, model.NewMoney(0)},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, tc.rule.Fee(tc.amount, tc.used), tc.name)
+	}
+}
+
+func TestFeeRule_Validate(t *testing.T) {
+	valid := []model.FeeRule{
+		{Type: model.FeeFlat, Flat: model.NewMoney(2500)},
+		{Type: model.FeePercentage, PercentBps: 50, Min: model.NewMoney(1000), Max: model.NewMoney(10000)},
+		{Type: model.FeeTiered, Tiers: []model.FeeTier{{UpTo: model.NewMoney(100000)}, {Flat: model.NewMoney(1000)}}},
+	}
+	for _, rule := range valid {
+		assert.NoError(t, rule.Validate())
+	}
+
+	invalid := []model.FeeRule{
+		{Type: "free"},
+		{Type: model.FeeFlat, Flat: model.NewMoney(-1)},
+		{Type: model.FeePercentage, Min: model.NewMoney(5000), Max: model.NewMoney(1000)},
+		{Type: model.FeeTiered},
+		{Type: model.FeeTiered, Tiers: []model.FeeTier{{Flat: model.NewMoney(1000)}, {UpTo: model.NewMoney(100000)}}},
+		{Type: model.FeeTiered, Tiers: []model.FeeTier{{UpTo: model.NewMoney(100000)}, {UpTo: model.NewMoney(50000)}}},
+	}
+	for _, rule := range invalid {
+		assert.ErrorIs(t, rule.Validate(), model.ErrInvalidFeeRule)
+	}
+}
+
+func TestFeeSchedule_RuleFallsBackToUnverified(t *testing.T) {
+	schedule := model.DefaultFeeSchedule()
+
+	rule, ok := schedule.Rule(model.FeeKindTopUp, model.TierVerified)
+	assert.True(t, ok)
+	assert.Equal(t, model.FeeFlat, rule.Type)
+
+	_, ok = model.FeeSchedule{}.Rule(model.FeeKindTransfer, model.TierVerified)
+	assert.False(t, ok)
+}
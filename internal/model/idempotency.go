@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// IdempotencyRecord stores the first response of a money-moving request so a
+// retry with the same Idempotency-Key replays it instead of executing again.
+// ResponseStatus 0 means the original request is still being processed.
+type IdempotencyRecord struct {
+	ID             int
+	UserID         int
+	Key            string
+	Endpoint       string
+	RequestHash    string
+	ResponseStatus int
+	ResponseBody   []byte
+	CreatedAt      time.Time
+}
+
+func (r *IdempotencyRecord) Completed() bool {
+	return r.ResponseStatus != 0
+}
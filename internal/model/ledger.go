@@ -0,0 +1,76 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// transaction_type values of the transactions history table
+const (
+	TransactionTypeTopUp       = "TOPUP"
+	TransactionTypeTransferIn  = "TRANSFER_IN"
+	TransactionTypeTransferOut = "TRANSFER_OUT"
+	// fee charged on a top-up or transfer, booked to SYSTEM:FEE_INCOME
+	TransactionTypeFee = "FEE"
+	// signed correction written by cmd/reconcile so the history adds up to wallets.balance
+	TransactionTypeAdjustment = "ADJUSTMENT"
+)
+
+// system ledger accounts, every wallet additionally has its own "WALLET:<wallet_id>" account
+const (
+	AccountTopUpFunding = "SYSTEM:TOPUP_FUNDING"
+	AccountFeeIncome    = "SYSTEM:FEE_INCOME"
+	AccountSuspense     = "SYSTEM:SUSPENSE"
+)
+
+const (
+	AccountTypeWallet = "WALLET"
+	AccountTypeSystem = "SYSTEM"
+)
+
+// journal entry types
+const (
+	JournalTopUp    = "TOPUP"
+	JournalTransfer = "TRANSFER"
+	JournalOpening  = "OPENING"
+)
+
+var (
+	ErrUnbalancedJournal = errors.New("Jurnal tidak seimbang (total posting harus nol)")
+	ErrEmptyPosting      = errors.New("Posting jurnal tidak boleh bernilai nol")
+)
+
+// Posting moves Amount into (positive) or out of (negative) one ledger account.
+type Posting struct {
+	AccountID int
+	Amount    Money
+}
+
+// JournalEntry groups the postings of one money movement. The postings of an entry always sum
+// to zero, so money is never created or destroyed, only moved between accounts.
+type JournalEntry struct {
+	ID          int
+	EntryType   string
+	Reference   string
+	Description string
+	Postings    []Posting
+	CreatedAt   time.Time
+}
+
+func (j *JournalEntry) Validate() error {
+	if len(j.Postings) < 2 {
+		return ErrUnbalancedJournal
+	}
+
+	var total Money
+	for _, p := range j.Postings {
+		if p.Amount.IsZero() {
+			return ErrEmptyPosting
+		}
+		total = total.Add(p.Amount)
+	}
+	if !total.IsZero() {
+		return ErrUnbalancedJournal
+	}
+	return nil
+}
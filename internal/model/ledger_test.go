@@ -0,0 +1,33 @@
+package model_test
+
+import (
+	"ewallet-service/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournalEntry_Validate(t *testing.T) {
+	amount := model.NewMoney(50000)
+
+	balanced := model.JournalEntry{Postings: []model.Posting{
+		{AccountID: 1, Amount: amount.Neg()},
+		{AccountID: 2, Amount: amount},
+	}}
+	assert.NoError(t, balanced.Validate())
+
+	unbalanced := model.JournalEntry{Postings: []model.Posting{
+		{AccountID: 1, Amount: amount.Neg()},
+		{AccountID: 2, Amount: model.NewMoney(49999)},
+	}}
+	assert.ErrorIs(t, unbalanced.Validate(), model.ErrUnbalancedJournal)
+
+	single := model.JournalEntry{Postings: []model.Posting{{AccountID: 1, Amount: amount}}}
+	assert.ErrorIs(t, single.Validate(), model.ErrUnbalancedJournal)
+
+	zero := model.JournalEntry{Postings: []model.Posting{
+		{AccountID: 1, Amount: model.Money{}},
+		{AccountID: 2, Amount: model.Money{}},
+	}}
+	assert.ErrorIs(t, zero.Validate(), model.ErrEmptyPosting)
+}
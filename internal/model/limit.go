@@ -0,0 +1,105 @@
+package model
+
+import "time"
+
+// limit tiers follow the verification level of the user
+const (
+	TierUnverified = "unverified"
+	TierVerified   = "verified"
+)
+
+var LimitTiers = []string{TierUnverified, TierVerified}
+
+// TierFor is the limit tier of a user; only a verified email unlocks the higher limits.
+func TierFor(emailVerified bool) string {
+	if emailVerified {
+		return TierVerified
+	}
+	return TierUnverified
+}
+
+// Limit caps one kind of money movement. A zero amount means no cap.
+type Limit struct {
+	PerTransaction Money `json:"per_transaction"`
+	Daily          Money `json:"daily"`
+	Monthly        Money `json:"monthly"`
+}
+
+// LimitTier is the set of limits of one tier. TransferIn is checked on the recipient.
+type LimitTier struct {
+	TopUp       Limit `json:"topup"`
+	TransferOut Limit `json:"transfer_out"`
+	TransferIn  Limit `json:"transfer_in"`
+}
+
+// DefaultLimitTiers are used unless TRANSACTION_LIMITS_FILE says otherwise.
+func DefaultLimitTiers() map[string]LimitTier {
+	return map[string]LimitTier{
+		TierUnverified: {
+			TopUp:       Limit{PerTransaction: NewMoney(2000000), Daily: NewMoney(5000000), Monthly: NewMoney(20000000)},
+			TransferOut: Limit{PerTransaction: NewMoney(1000000), Daily: NewMoney(2000000), Monthly: NewMoney(10000000)},
+			TransferIn:  Limit{Daily: NewMoney(5000000), Monthly: NewMoney(20000000)},
+		},
+		TierVerified: {
+			TopUp:       Limit{PerTransaction: NewMoney(10000000), Daily: NewMoney(20000000), Monthly: NewMoney(40000000)},
+			TransferOut: Limit{PerTransaction: NewMoney(10000000), Daily: NewMoney(25000000), Monthly: NewMoney(100000000)},
+			TransferIn:  Limit{Daily: NewMoney(25000000), Monthly: NewMoney(100000000)},
+		},
+	}
+}
+
+// Usage is how much moved so far in the current day and month, and in how many transactions this month.
+type Usage struct {
+	Daily        Money
+	Monthly      Money
+	MonthlyCount int
+}
+
+// LimitUsage is what the repository aggregates from the history of one wallet.
+type LimitUsage struct {
+	Tier        string
+	TopUp       Usage
+	TransferOut Usage
+	TransferIn  Usage
+}
+
+// Equal reports whether nothing was topped up, sent or received between two reads of the usage.
+func (u LimitUsage) Equal(o LimitUsage) bool {
+	return u.Tier == o.Tier && u.TopUp.equal(o.TopUp) && u.TransferOut.equal(o.TransferOut) && u.TransferIn.equal(o.TransferIn)
+}
+
+func (u Usage) equal(o Usage) bool {
+	return u.Daily.Cmp(o.Daily) == 0 && u.Monthly.Cmp(o.Monthly) == 0 && u.MonthlyCount == o.MonthlyCount
+}
+
+// UsageCheck is the usage a top-up or transfer was limited and priced against. The repository reads it
+// again once the users are locked and refuses the transaction when it moved in between.
+// A nil Sender or Recipient is not checked.
+type UsageCheck struct {
+	DayStart   time.Time
+	MonthStart time.Time
+	Sender     *LimitUsage
+	Recipient  *LimitUsage
+}
+
+// LimitWindow is one cumulative limit as shown to the user; Limit and Remaining are null without a cap.
+type LimitWindow struct {
+	Limit     *Money    `json:"limit"`
+	Used      Money     `json:"used"`
+	Remaining *Money    `json:"remaining"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
+
+type LimitStatus struct {
+	PerTransaction *Money      `json:"per_transaction"`
+	Daily          LimitWindow `json:"daily"`
+	Monthly        LimitWindow `json:"monthly"`
+}
+
+// LimitsResponse is the body of GET /limits.
+type LimitsResponse struct {
+	Tier        string      `json:"tier"`
+	TopUp       LimitStatus `json:"topup"`
+	TransferOut LimitStatus `json:"transfer_out"`
+	TransferIn  LimitStatus `json:"transfer_in"`
+}
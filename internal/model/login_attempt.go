@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// LoginAttempt counts recent failed logins of one key ("account:<email>" or "ip:<address>").
+// The zero value means no failures.
+type LoginAttempt struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
+
+// Locked reports whether the key is locked at the given time.
+func (a LoginAttempt) Locked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
@@ -0,0 +1,221 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of every wallet. Amounts are stored with
+// two decimal places (DECIMAL(15, 2)), so one unit = 100 minor units (sen).
+const (
+	DefaultCurrency = "IDR"
+	minorPerUnit    = 100
+	moneyScale      = 2
+)
+
+var ErrInvalidMoney = errors.New("Format nominal tidak valid")
+
+// Money is an exact amount in minor units plus its currency.
+// It never goes through float64: the API sends it as a decimal string ("50000.00")
+// and the database column (DECIMAL) is read and written as text.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// NewMoney builds Money from whole currency units (e.g. 50000 -> "50000.00").
+func NewMoney(units int64) Money {
+	return Money{Amount: units * minorPerUnit, Currency: DefaultCurrency}
+}
+
+// MoneyFromMinor builds Money from minor units (e.g. 5000050 -> "50000.50").
+func MoneyFromMinor(minor int64) Money {
+	return Money{Amount: minor, Currency: DefaultCurrency}
+}
+
+// ParseMoney parses a decimal string with at most two fraction digits.
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Money{}, ErrInvalidMoney
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	intPart, fracPart, hasDot := strings.Cut(s, ".")
+	if intPart == "" || (hasDot && fracPart == "") || len(fracPart) > moneyScale {
+		return Money{}, ErrInvalidMoney
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return Money{}, ErrInvalidMoney
+	}
+
+	units, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil || units > (1<<63-1)/minorPerUnit-1 {
+		return Money{}, ErrInvalidMoney
+	}
+
+	fracPart += strings.Repeat("0", moneyScale-len(fracPart))
+	minor, _ := strconv.ParseInt(fracPart, 10, 64)
+
+	amount := units*minorPerUnit + minor
+	if negative {
+		amount = -amount
+	}
+	return Money{Amount: amount, Currency: DefaultCurrency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the amount as a plain decimal, e.g. "50000.00".
+func (m Money) String() string {
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/minorPerUnit, amount%minorPerUnit)
+}
+
+func (m Money) Add(o Money) Money {
+	return Money{Amount: m.Amount + o.Amount, Currency: m.currency()}
+}
+
+func (m Money) Sub(o Money) Money {
+	return Money{Amount: m.Amount - o.Amount, Currency: m.currency()}
+}
+
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.currency()}
+}
+
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m.Amount < o.Amount:
+		return -1
+	case m.Amount > o.Amount:
+		return 1
+	}
+	return 0
+}
+
+func (m Money) LessThan(o Money) bool {
+	return m.Amount < o.Amount
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+func (m Money) currency() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+// MarshalJSON encodes the amount as a string so clients never parse it as a float.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON accepts both "50000.00" and a bare number literal (50000).
+// The literal is parsed as text, never as float64.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		*m = Money{}
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return ErrInvalidMoney
+		}
+	}
+
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// UnmarshalParam lets gin bind Money from query/form parameters (?min_amount=10000).
+func (m *Money) UnmarshalParam(param string) error {
+	parsed, err := ParseMoney(param)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner. pgx returns DECIMAL columns as text.
+func (m *Money) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*m = Money{Currency: DefaultCurrency}
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		*m = NewMoney(v)
+		return nil
+	case float64:
+		s = strconv.FormatFloat(v, 'f', moneyScale, 64)
+	default:
+		return fmt.Errorf("Tipe nominal tidak didukung: %T", src)
+	}
+
+	parsed, err := parseDBMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, the text form is cast to DECIMAL by Postgres.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// parseDBMoney trims trailing zeros beyond the scale (e.g. SUM() over DECIMAL
+// may come back as "150000.0000") before parsing.
+func parseDBMoney(s string) (Money, error) {
+	if intPart, fracPart, ok := strings.Cut(s, "."); ok && len(fracPart) > moneyScale {
+		extra := strings.TrimRight(fracPart[moneyScale:], "0")
+		if extra != "" {
+			return Money{}, ErrInvalidMoney
+		}
+		s = intPart + "." + fracPart[:moneyScale]
+	}
+	return ParseMoney(s)
+}
@@ -0,0 +1,75 @@
+package model_test
+
+import (
+	"encoding/json"
+	"ewallet-service/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	cases := map[string]int64{
+		"50000":     5000000,
+		"50000.5":   5000050,
+		"50000.05":  5000005,
+		"0.01":      1,
+		"-1500.25":  -150025,
+		" 10000.00": 1000000,
+	}
+
+	for in, want := range cases {
+		m, err := model.ParseMoney(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, want, m.Amount, in)
+		assert.Equal(t, model.DefaultCurrency, m.Currency, in)
+	}
+
+	for _, in := range []string{"", "abc", "1.234", "1.", ".5", "1e5", "--1"} {
+		_, err := model.ParseMoney(in)
+		assert.ErrorIs(t, err, model.ErrInvalidMoney, in)
+	}
+}
+
+func TestMoney_NoFloatDrift(t *testing.T) {
+	// 0.1 + 0.2 != 0.3 in float64, but must be exact here
+	total := model.Money{}
+	for i := 0; i < 1000; i++ {
+		total = total.Add(model.MoneyFromMinor(10))
+	}
+	assert.Equal(t, "100.00", total.String())
+
+	a, _ := model.ParseMoney("0.1")
+	b, _ := model.ParseMoney("0.2")
+	assert.Equal(t, "0.30", a.Add(b).String())
+}
+
+func TestMoney_JSON(t *testing.T) {
+	var req model.TopUpRequest
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":"12500.50"}`), &req))
+	assert.Equal(t, int64(1250050), req.Amount.Amount)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":12500}`), &req))
+	assert.Equal(t, model.NewMoney(12500), req.Amount)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"12.345"}`), &req))
+
+	out, err := json.Marshal(model.Wallet{Balance: model.MoneyFromMinor(-5)})
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), `"balance":"-0.05"`)
+}
+
+func TestMoney_Scan(t *testing.T) {
+	var m model.Money
+	assert.NoError(t, m.Scan("150000.00"))
+	assert.Equal(t, model.NewMoney(150000), m)
+
+	assert.NoError(t, m.Scan([]byte("75.5000")))
+	assert.Equal(t, int64(7550), m.Amount)
+
+	assert.Error(t, m.Scan("1.005"))
+
+	v, err := model.NewMoney(42).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "42.00", v)
+}
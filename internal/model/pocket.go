@@ -0,0 +1,23 @@
+package model
+
+const (
+	// PrimaryPocketName is the name of the wallet opened at registration
+	PrimaryPocketName = "Utama"
+	// MaxPockets is how many wallets (the primary one included) a user can have
+	MaxPockets = 10
+)
+
+// CreatePocketRequest opens a named pocket: POST /pockets
+type CreatePocketRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+// PocketMoveRequest moves money between two pockets of the same user: POST /pockets/move.
+// An empty FromWalletNumber means the primary pocket.
+type PocketMoveRequest struct {
+	FromWalletNumber string `json:"from_wallet_number,omitempty" binding:"omitempty,wallet_number"`
+	ToWalletNumber   string `json:"to_wallet_number" binding:"required,wallet_number"`
+	Amount           Money  `json:"amount" binding:"required,money_min=1"`
+	Description      string `json:"description" binding:"max=255"`
+	PIN              string `json:"pin" binding:"required,len=6,numeric"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// WalletReconciliation compares the cached wallet balance with the balance
+// recomputed from its transaction history and with the sum of its ledger postings.
+type WalletReconciliation struct {
+	WalletID         int    `json:"wallet_id"`
+	WalletNumber     string `json:"wallet_number"`
+	Balance          Money  `json:"balance"`
+	HistoryBalance   Money  `json:"history_balance"`
+	LedgerBalance    Money  `json:"ledger_balance"`
+	Difference       Money  `json:"difference"`
+	TransactionCount int    `json:"transaction_count"`
+	Adjusted         bool   `json:"adjusted"`
+}
+
+func (w WalletReconciliation) Matches() bool {
+	return w.Balance.Cmp(w.HistoryBalance) == 0 && w.LedgerMatches()
+}
+
+// LedgerMatches reports whether the postings add up to the cached balance. A ledger mismatch
+// is only reported: adjustments fix the history, never the ledger.
+func (w WalletReconciliation) LedgerMatches() bool {
+	return w.Balance.Cmp(w.LedgerBalance) == 0
+}
+
+type ReconcileReport struct {
+	GeneratedAt     time.Time              `json:"generated_at"`
+	WalletsChecked  int                    `json:"wallets_checked"`
+	TotalDifference Money                  `json:"total_difference"`
+	Applied         bool                   `json:"applied"`
+	Discrepancies   []WalletReconciliation `json:"discrepancies"`
+}
+
+// Unresolved counts the discrepancies still wrong after the run: those not adjusted, and those
+// whose ledger disagrees, which no adjustment fixes.
+func (r ReconcileReport) Unresolved() int {
+	n := 0
+	for _, d := range r.Discrepancies {
+		if !d.Adjusted || !d.LedgerMatches() {
+			n++
+		}
+	}
+	return n
+}
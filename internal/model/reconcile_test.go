@@ -0,0 +1,20 @@
+package model_test
+
+import (
+	"ewallet-service/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcileReport_Unresolved(t *testing.T) {
+	balance := model.NewMoney(1000)
+
+	historyFixed := model.WalletReconciliation{Balance: balance, HistoryBalance: model.NewMoney(900), LedgerBalance: balance, Adjusted: true}
+	ledgerOnly := model.WalletReconciliation{Balance: balance, HistoryBalance: balance, LedgerBalance: model.NewMoney(900)}
+	bothAdjusted := model.WalletReconciliation{Balance: balance, HistoryBalance: model.NewMoney(900), LedgerBalance: model.NewMoney(900), Adjusted: true}
+
+	assert.Equal(t, 0, model.ReconcileReport{Discrepancies: []model.WalletReconciliation{historyFixed}}.Unresolved())
+	assert.Equal(t, 1, model.ReconcileReport{Discrepancies: []model.WalletReconciliation{historyFixed, ledgerOnly}}.Unresolved())
+	assert.Equal(t, 1, model.ReconcileReport{Discrepancies: []model.WalletReconciliation{bothAdjusted}}.Unresolved())
+}
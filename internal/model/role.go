@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// roles, stored in users.role and carried in the access token
+const (
+	RoleCustomer = "customer"
+	RoleMerchant = "merchant"
+	RoleSupport  = "support"
+	RoleAdmin    = "admin"
+)
+
+// UserAccount is the back-office view of a user and their wallets. Wallet is the primary pocket, or the
+// pocket that was looked up by number; Pockets are all of them, primary first, and only filled for a single account.
+type UserAccount struct {
+	ID            int        `json:"id"`
+	Name          string     `json:"name"`
+	Email         string     `json:"email"`
+	Role          string     `json:"role"`
+	EmailVerified bool       `json:"email_verified"`
+	TOTPEnabled   bool       `json:"two_factor_enabled"`
+	FrozenAt      *time.Time `json:"frozen_at,omitempty"`
+	FrozenReason  string     `json:"frozen_reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Wallet        *Wallet    `json:"wallet,omitempty"`
+	Pockets       []Wallet   `json:"pockets,omitempty"`
+}
+
+// UserSearch filters GET /admin/users, at least one field is needed.
+type UserSearch struct {
+	Email        string `form:"email"`
+	Name         string `form:"name"`
+	WalletNumber string `form:"wallet_number"`
+	Limit        int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type FreezeAccountRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type ChangeRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=customer merchant support admin"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+type Session struct {
+	ID        string
+	UserID    int
+	Email     string
+	Role      string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+// AccessClaims is what AuthMiddleware puts into the gin context (key "claims").
+type AccessClaims struct {
+	UserID    int
+	Email     string
+	Role      string
+	SessionID string
+	JTI       string
+	ExpiresAt time.Time
+}
+
+// ChallengeClaims identify the challenge token of a 2FA login between its two steps.
+type ChallengeClaims struct {
+	UserID    int
+	JTI       string
+	ExpiresAt time.Time
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
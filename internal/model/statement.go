@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+type StatementRequest struct {
+	Month  string `form:"month" binding:"required,datetime=2006-01"`
+	Format string `form:"format" binding:"omitempty,oneof=csv pdf"`
+	// WalletNumber picks one of the user's pockets; empty means the primary one
+	WalletNumber string `form:"wallet_number" binding:"omitempty,wallet_number"`
+}
+
+// Statement is the monthly account statement of one wallet: the opening balance, every
+// movement of the period with the running balance, and the closing balance.
+type Statement struct {
+	WalletNumber   string          `json:"wallet_number"`
+	OwnerName      string          `json:"owner_name"`
+	PeriodStart    time.Time       `json:"period_start"`
+	PeriodEnd      time.Time       `json:"period_end"`
+	OpeningBalance Money           `json:"opening_balance"`
+	TotalIn        Money           `json:"total_in"`
+	TotalOut       Money           `json:"total_out"`
+	ClosingBalance Money           `json:"closing_balance"`
+	Lines          []StatementLine `json:"lines"`
+	GeneratedAt    time.Time       `json:"generated_at"`
+}
+
+// StatementLine has either Credit (money in) or Debit (money out) set.
+type StatementLine struct {
+	TransactionID   int       `json:"transaction_id"`
+	Date            time.Time `json:"date"`
+	TransactionType string    `json:"transaction_type"`
+	Description     string    `json:"description"`
+	Reference       string    `json:"reference"`
+	Credit          Money     `json:"credit"`
+	Debit           Money     `json:"debit"`
+	Balance         Money     `json:"balance"`
+}
@@ -6,34 +6,104 @@ type Transaction struct {
 	ID              int       `json:"id"`
 	WalletID        int       `json:"wallet_id"`
 	TransactionType string    `json:"transaction_type"`
-	Amount          float64   `json:"amount"`
+	Amount          Money     `json:"amount"`
 	Description     string    `json:"description"`
+	TransferRef     string    `json:"transfer_reference,omitempty"`
 	CreatedAt       time.Time `json:"created_at"`
 }
 
+// SignedAmount is the effect of the row on the wallet's history balance:
+// positive for money in, negative for money out. ADJUSTMENT rows are already signed.
+func (t Transaction) SignedAmount() Money {
+	if t.TransactionType == TransactionTypeTransferOut || t.TransactionType == TransactionTypeFee {
+		return t.Amount.Neg()
+	}
+	return t.Amount
+}
+
+// TransactionDetail is a single history row with everything the owner may see about it.
+// BalanceBefore/BalanceAfter are empty for rows recorded before balances were tracked.
+type TransactionDetail struct {
+	Transaction
+	BalanceBefore *Money        `json:"balance_before,omitempty"`
+	BalanceAfter  *Money        `json:"balance_after,omitempty"`
+	Counterparty  *Counterparty `json:"counterparty,omitempty"`
+}
+
+// Counterparty is the other side of a transfer. Name is masked before it leaves the usecase.
+type Counterparty struct {
+	WalletNumber string `json:"wallet_number"`
+	Name         string `json:"name"`
+}
+
+// TransactionFilter is bound from the query string of GET /transactions.
+type TransactionFilter struct {
+	Cursor          string     `form:"cursor"`
+	Limit           int        `form:"limit" binding:"omitempty,min=1,max=100"`
+	TransactionType string     `form:"transaction_type" binding:"omitempty,oneof=TOPUP TRANSFER_IN TRANSFER_OUT FEE ADJUSTMENT"`
+	From            *time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To              *time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`
+	MinAmount       *Money     `form:"min_amount"`
+	MaxAmount       *Money     `form:"max_amount"`
+
+	// WalletID narrows the history to one pocket of the user (the back-office wallet view), zero means all
+	WalletID int `form:"-"`
+
+	// keyset position decoded from Cursor: rows strictly older than (AfterCreatedAt, AfterID)
+	AfterCreatedAt time.Time `form:"-"`
+	AfterID        int       `form:"-"`
+}
+
+type TransactionPage struct {
+	Items      []Transaction `json:"items"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
 type TopUpRequest struct {
-	Amount float64 `json:"amount" binding:"required,min=10000"`
+	Amount Money  `json:"amount" binding:"required,money_min=10000"`
+	PIN    string `json:"pin" binding:"omitempty,len=6,numeric"`
 }
 
 type TopUpResponse struct {
 	ID            int       `json:"id"`
 	WalletNumber  string    `json:"wallet_number"`
-	BalanceBefore float64   `json:"balance_before"`
-	BalanceAfter  float64   `json:"balance_after"`
-	TopUpAmount   float64   `json:"topup_amount"`
+	BalanceBefore Money     `json:"balance_before"`
+	BalanceAfter  Money     `json:"balance_after"`
+	TopUpAmount   Money     `json:"topup_amount"`
+	Fee           Money     `json:"fee"`
 	CreatedAt     time.Time `json:"created_at"`
 }
 
 type TransferRequest struct {
-	TargetWalletNumber string  `json:"target_wallet_number" binding:"required"`
-	Amount             float64 `json:"amount" binding:"required,min=1000"`
-	Description        string  `json:"description"`
+	// SourceWalletNumber picks the sender's pocket; empty means the primary one
+	SourceWalletNumber string `json:"source_wallet_number,omitempty" binding:"omitempty,wallet_number"`
+	TargetWalletNumber string `json:"target_wallet_number" binding:"required,wallet_number"`
+	Amount             Money  `json:"amount" binding:"required,money_min=1000"`
+	Description        string `json:"description" binding:"max=255"`
+	PIN                string `json:"pin" binding:"required,len=6,numeric"`
+	// OTP is only needed above the 2FA threshold
+	OTP string `json:"otp,omitempty" binding:"omitempty,len=6,numeric"`
+	// QuoteID is set when the transfer confirms a quote, which is then used up in the same database transaction
+	QuoteID string `json:"-"`
 }
 
+// TransferResponse.ID is the transfer reference (ULID), usable with GET /transfers/:reference
 type TransferResponse struct {
 	ID             string    `json:"id"`
-	SenderBalance  float64   `json:"sender_balance"`
+	SenderWallet   string    `json:"sender_wallet"`
+	SenderBalance  Money     `json:"sender_balance"`
+	ReceiverWallet string    `json:"receiver_wallet"`
+	Amount         Money     `json:"amount"`
+	Fee            Money     `json:"fee"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type Transfer struct {
+	Reference      string    `json:"reference"`
+	SenderWallet   string    `json:"sender_wallet"`
 	ReceiverWallet string    `json:"receiver_wallet"`
-	Amount         float64   `json:"amount"`
+	Amount         Money     `json:"amount"`
+	Fee            Money     `json:"fee"`
+	Description    string    `json:"description"`
 	CreatedAt      time.Time `json:"created_at"`
 }
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// TransferQuoteRequest starts a two-phase transfer: POST /transfers/quote
+type TransferQuoteRequest struct {
+	SourceWalletNumber string `json:"source_wallet_number,omitempty" binding:"omitempty,wallet_number"`
+	TargetWalletNumber string `json:"target_wallet_number" binding:"required,wallet_number"`
+	Amount             Money  `json:"amount" binding:"required,money_min=1000"`
+	Description        string `json:"description" binding:"max=255"`
+}
+
+// TransferQuote is what the user confirms: the recipient (name masked), the amount and the fee.
+// Confirming prices the transfer again and charges the current fee, but never more than Fee:
+// a higher one refuses the quote (TRANSFER_QUOTE_FEE_CHANGED). It can be confirmed once, until ExpiresAt.
+type TransferQuote struct {
+	ID                 string    `json:"quote_id"`
+	UserID             int       `json:"-"`
+	SourceWalletNumber string    `json:"source_wallet_number,omitempty"`
+	TargetWalletNumber string    `json:"target_wallet_number"`
+	RecipientName      string    `json:"recipient_name"`
+	Amount             Money     `json:"amount"`
+	Fee                Money     `json:"fee"`
+	Total              Money     `json:"total"`
+	Description        string    `json:"description"`
+	ExpiresAt          time.Time `json:"expires_at"`
+}
+
+// TransferConfirmRequest executes a quote: POST /transfers/confirm
+type TransferConfirmRequest struct {
+	QuoteID string `json:"quote_id" binding:"required,len=26"`
+	PIN     string `json:"pin" binding:"required,len=6,numeric"`
+	OTP     string `json:"otp,omitempty" binding:"omitempty,len=6,numeric"`
+}
+
+// Recipient is the wallet a transfer would credit, as found by its number.
+type Recipient struct {
+	WalletID     int
+	UserID       int
+	WalletNumber string
+	Name         string
+	Status       string
+}
+
+// WalletInquiryRequest is bound from the path of GET /wallets/:number/inquiry.
+type WalletInquiryRequest struct {
+	Number string `uri:"number" binding:"required,wallet_number"`
+}
+
+// WalletInquiry answers GET /wallets/:number/inquiry before a transfer. Why a wallet cannot
+// receive is not disclosed, as with RECIPIENT_WALLET_UNAVAILABLE.
+type WalletInquiry struct {
+	WalletNumber string `json:"wallet_number"`
+	OwnerName    string `json:"owner_name"`
+	CanReceive   bool   `json:"can_receive"`
+	OwnWallet    bool   `json:"own_wallet"`
+}
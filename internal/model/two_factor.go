@@ -0,0 +1,38 @@
+package model
+
+// TOTPState is the stored 2FA setup of a user. Secret is empty when the user never enrolled,
+// Enabled stays false until the first code is confirmed.
+type TOTPState struct {
+	Secret  string
+	Enabled bool
+	// LastStep is the last accepted time step, codes from that step or earlier are refused
+	LastStep int64
+}
+
+type TOTPEnrollResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TOTPConfirmRequest struct {
+	OTP string `json:"otp" binding:"required,len=6,numeric"`
+}
+
+// TOTPConfirmResponse is the only time the recovery codes are shown.
+type TOTPConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type DisableTOTPRequest struct {
+	Password string `json:"password" binding:"required"`
+	OTP      string `json:"otp" binding:"required,len=6,numeric"`
+}
+
+// LoginTwoFactorRequest is the second login step: the challenge token from POST /login plus
+// either the current OTP or one of the recovery codes.
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	OTP            string `json:"otp" binding:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode   string `json:"recovery_code" binding:"required_without=OTP"`
+	ClientIP       string `json:"-"`
+}
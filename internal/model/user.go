@@ -3,19 +3,28 @@ package model
 import "time"
 
 type User struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Password  string    `json:"-"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"-"`
+	// TOTPEnabled means login needs a second step (see LoginTwoFactorRequest)
+	TOTPEnabled   bool      `json:"-"`
+	EmailVerified bool      `json:"-"`
+	Role          string    `json:"role"`
+	Frozen        bool      `json:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
+// Wallet is one pocket of the user; IsPrimary marks the one opened at registration.
 type Wallet struct {
 	ID           int       `json:"id"`
 	UserID       int       `json:"user_id"`
-	Balance      float64   `json:"balance"`
+	Name         string    `json:"name"`
+	IsPrimary    bool      `json:"is_primary"`
+	Balance      Money     `json:"balance"`
 	WalletNumber string    `json:"wallet_number"`
+	Status       string    `json:"status"`
 	CreatedAt    time.Time `json:"created_at"`
 }
 
@@ -27,20 +36,44 @@ type RegisterRequest struct {
 }
 
 type RegisterResponse struct {
-	ID           int     `json:"id"`
-	Name         string  `json:"name"`
-	Email        string  `json:"email"`
-	WalletNumber string  `json:"wallet_number"`
-	Balance      float64 `json:"balance"`
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	WalletNumber string `json:"wallet_number"`
+	Balance      Money  `json:"balance"`
 }
 
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+	// ClientIP is filled by the handler for the per-IP attempt counter
+	ClientIP string `json:"-"`
 }
 
+// LoginResponse carries the tokens, or only a challenge token when the user has 2FA enabled.
 type LoginResponse struct {
-	AccessToken string `json:"access_token"`
-	Type        string `json:"token_type"`
-	ExpiresIn   string  `json:"expires_in"` 
+	AccessToken       string `json:"access_token,omitempty"`
+	Type              string `json:"token_type,omitempty"`
+	ExpiresIn         string `json:"expires_in,omitempty"`
+	RefreshToken      string `json:"refresh_token,omitempty"`
+	RefreshExpiresIn  string `json:"refresh_expires_in,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+}
+
+// PINState is the stored transaction PIN of a user. Hash is empty when no PIN was set yet.
+type PINState struct {
+	Hash           string
+	FailedAttempts int
+	LockedUntil    *time.Time
+}
+
+type SetPINRequest struct {
+	Password string `json:"password" binding:"required"`
+	PIN      string `json:"pin" binding:"required,len=6,numeric"`
+}
+
+type ChangePINRequest struct {
+	OldPIN string `json:"old_pin" binding:"required,len=6,numeric"`
+	NewPIN string `json:"new_pin" binding:"required,len=6,numeric"`
 }
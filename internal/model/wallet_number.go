@@ -0,0 +1,77 @@
+package model
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// Wallet numbers are WalletNumberPrefix, 8 random digits and a Luhn check digit: 12 digits,
+// e.g. 100482913375. The check digit catches any single mistyped digit and most swapped
+// neighbours before a transfer reaches the database.
+const (
+	WalletNumberPrefix = "100"
+	WalletNumberLength = 12
+)
+
+// legacyWalletNumberLength is the longest number issued before check digits ("100" + up to 7 digits).
+// Those wallets keep their numbers, so they are still accepted, without a check digit.
+const legacyWalletNumberLength = 10
+
+// GenerateWalletNumber returns a new random wallet number. It is not checked for uniqueness,
+// the caller retries on a collision.
+func GenerateWalletNumber() (string, error) {
+	random := WalletNumberLength - len(WalletNumberPrefix) - 1
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(random)), nil)
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+
+	digits := WalletNumberPrefix + leftPad(n.String(), random)
+	return digits + string(luhnCheckDigit(digits)), nil
+}
+
+// ValidWalletNumber accepts a 12-digit number with a correct check digit, or a legacy number.
+func ValidWalletNumber(number string) bool {
+	if number == "" {
+		return false
+	}
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	switch {
+	case len(number) == WalletNumberLength:
+		return luhnCheckDigit(number[:len(number)-1]) == number[len(number)-1]
+	case len(number) <= legacyWalletNumberLength:
+		return len(number) > len(WalletNumberPrefix) && number[:len(WalletNumberPrefix)] == WalletNumberPrefix
+	}
+	return false
+}
+
+// luhnCheckDigit is the digit that makes digits+check pass the Luhn test: every second digit
+// from the right of digits is doubled (9 subtracted above 9), and the sum must end in 0.
+func luhnCheckDigit(digits string) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func leftPad(digits string, length int) string {
+	for len(digits) < length {
+		digits = "0" + digits
+	}
+	return digits
+}
@@ -0,0 +1,48 @@
+package model_test
+
+import (
+	"ewallet-service/internal/model"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateWalletNumber(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		number, err := model.GenerateWalletNumber()
+
+		assert.NoError(t, err)
+		assert.Len(t, number, model.WalletNumberLength)
+		assert.True(t, strings.HasPrefix(number, model.WalletNumberPrefix))
+		assert.True(t, model.ValidWalletNumber(number), number)
+		seen[number] = true
+	}
+	assert.Greater(t, len(seen), 90)
+}
+
+func TestValidWalletNumber(t *testing.T) {
+	cases := []struct {
+		name   string
+		number string
+		want   bool
+	}{
+		{"check digit", "100123456780", true},
+		{"mistyped digit", "100123456880", false},
+		{"swapped digits", "100123465780", false},
+		{"wrong check digit", "100123456781", false},
+		{"legacy number", "1004821337", true},
+		{"short legacy number", "1007", true},
+		{"legacy without prefix", "2004821337", false},
+		{"too long", "1001234567801", false},
+		{"letters", "10012345678O", false},
+		{"empty", "", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, model.ValidWalletNumber(tc.number))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package model
+
+// wallet states, stored in wallets.status
+const (
+	WalletActive = "active"
+	// WalletFrozen stops all money movement until compliance lifts it
+	WalletFrozen = "frozen"
+	// WalletClosed is final, the wallet can no longer be reactivated
+	WalletClosed = "closed"
+	// WalletDebitBlocked can still receive money but not send it
+	WalletDebitBlocked = "debit_blocked"
+	// WalletCreditBlocked can still send money but not receive it
+	WalletCreditBlocked = "credit_blocked"
+)
+
+// WalletCanDebit reports whether money may leave a wallet in this state.
+func WalletCanDebit(status string) bool {
+	return status == WalletActive || status == WalletCreditBlocked
+}
+
+// WalletCanCredit reports whether money may enter a wallet in this state.
+func WalletCanCredit(status string) bool {
+	return status == WalletActive || status == WalletDebitBlocked
+}
+
+// WalletStatusTightens reports whether moving from one state to another only adds restrictions:
+// freezing a wallet from any state, or blocking one side of an active wallet.
+func WalletStatusTightens(from, to string) bool {
+	if to == WalletFrozen {
+		return true
+	}
+	return from == WalletActive && (to == WalletDebitBlocked || to == WalletCreditBlocked)
+}
+
+type ChangeWalletStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active frozen closed debit_blocked credit_blocked"`
+	Reason string `json:"reason" binding:"required,max=500"`
+}
@@ -0,0 +1,27 @@
+package model_test
+
+import (
+	"ewallet-service/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletStatus_DebitAndCredit(t *testing.T) {
+	cases := []struct {
+		status        string
+		debit, credit bool
+	}{
+		{model.WalletActive, true, true},
+		{model.WalletFrozen, false, false},
+		{model.WalletClosed, false, false},
+		{model.WalletDebitBlocked, false, true},
+		{model.WalletCreditBlocked, true, false},
+		{"", false, false},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.debit, model.WalletCanDebit(tc.status), tc.status)
+		assert.Equal(t, tc.credit, model.WalletCanCredit(tc.status), tc.status)
+	}
+}
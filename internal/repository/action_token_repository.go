@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"ewallet-service/internal/model"
+)
+
+var ErrActionTokenInvalid = errors.New("Token tidak valid, kadaluarsa atau sudah dipakai")
+
+type ActionTokenRepository interface {
+	Create(ctx context.Context, token *model.ActionToken) error
+	Consume(ctx context.Context, jti, purpose string) (int, error)
+	InvalidateUnused(ctx context.Context, userID int, purpose string) error
+}
+
+type actionTokenRepositoryPostgres struct {
+	DB *sql.DB
+}
+
+func NewActionTokenRepository(db *sql.DB) ActionTokenRepository {
+	return &actionTokenRepositoryPostgres{DB: db}
+}
+
+func (r *actionTokenRepositoryPostgres) Create(ctx context.Context, token *model.ActionToken) error {
+	query := "INSERT INTO action_tokens (jti, user_id, purpose, expires_at) VALUES ($1, $2, $3, $4)"
+	_, err := r.DB.ExecContext(ctx, query, token.JTI, token.UserID, token.Purpose, token.ExpiresAt)
+	return err
+}
+
+// Consume marks the token used and returns its user. Of two requests with the same token
+// only one gets past the UPDATE.
+func (r *actionTokenRepositoryPostgres) Consume(ctx context.Context, jti, purpose string) (int, error) {
+	query := `
+		UPDATE action_tokens SET used_at = NOW()
+		WHERE jti = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id
+	`
+
+	var userID int
+	err := r.DB.QueryRowContext(ctx, query, jti, purpose).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, ErrActionTokenInvalid
+	}
+	return userID, err
+}
+
+// InvalidateUnused retires the outstanding tokens of a purpose, so only the newest link works.
+func (r *actionTokenRepositoryPostgres) InvalidateUnused(ctx context.Context, userID int, purpose string) error {
+	query := "UPDATE action_tokens SET used_at = NOW() WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL"
+	_, err := r.DB.ExecContext(ctx, query, userID, purpose)
+	return err
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"ewallet-service/internal/model"
+	"fmt"
+	"strings"
+)
+
+// AdminRepository backs the back-office API; unlike the other repositories it is not scoped to the caller.
+type AdminRepository interface {
+	SearchUsers(ctx context.Context, search model.UserSearch) ([]model.UserAccount, error)
+	FindUserAccount(ctx context.Context, userID int) (*model.UserAccount, error)
+	FindAccountByWalletNumber(ctx context.Context, walletNumber string) (*model.UserAccount, error)
+	SetFrozen(ctx context.Context, userID int, reason *string) error
+	SetRole(ctx context.Context, userID int, role string) error
+	SetWalletStatus(ctx context.Context, walletIDs []int, status, reason string) error
+}
+
+type adminRepositoryPostgres struct {
+	DB *sql.DB
+}
+
+func NewAdminRepository(db *sql.DB) AdminRepository {
+	return &adminRepositoryPostgres{DB: db}
+}
+
+const userAccountColumns = `
+	SELECT u.id, u.name, u.email, u.role, u.email_verified_at IS NOT NULL, u.totp_enabled,
+		u.frozen_at, COALESCE(u.frozen_reason, ''), u.created_at,
+		w.id, w.balance, w.wallet_number, w.status, w.created_at
+	FROM users u
+`
+
+// an account is shown with its primary pocket
+const userAccountSelect = userAccountColumns + " LEFT JOIN wallets w ON w.user_id = u.id AND w.is_primary"
+
+// SearchUsers matches email and name case-insensitively by substring, wallet number exactly.
+func (r *adminRepositoryPostgres) SearchUsers(ctx context.Context, search model.UserSearch) ([]model.UserAccount, error) {
+	var conditions []string
+	var args []interface{}
+
+	if search.Email != "" {
+		args = append(args, "%"+search.Email+"%")
+		conditions = append(conditions, fmt.Sprintf("u.email ILIKE $%d", len(args)))
+	}
+	if search.Name != "" {
+		args = append(args, "%"+search.Name+"%")
+		conditions = append(conditions, fmt.Sprintf("u.name ILIKE $%d", len(args)))
+	}
+	if search.WalletNumber != "" {
+		args = append(args, search.WalletNumber)
+		// any pocket of the user, the account shows the primary one
+		conditions = append(conditions, fmt.Sprintf("u.id IN (SELECT user_id FROM wallets WHERE wallet_number = $%d)", len(args)))
+	}
+
+	query := userAccountSelect
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, search.Limit)
+	query += fmt.Sprintf(" ORDER BY u.id LIMIT $%d", len(args))
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []model.UserAccount{}
+	for rows.Next() {
+		account, err := scanUserAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *account)
+	}
+	return accounts, rows.Err()
+}
+
+func (r *adminRepositoryPostgres) FindUserAccount(ctx context.Context, userID int) (*model.UserAccount, error) {
+	account, err := scanUserAccount(r.DB.QueryRowContext(ctx, userAccountSelect+" WHERE u.id = $1", userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.withPockets(ctx, account)
+}
+
+// FindAccountByWalletNumber shows the account with the pocket numbered walletNumber, primary or not.
+func (r *adminRepositoryPostgres) FindAccountByWalletNumber(ctx context.Context, walletNumber string) (*model.UserAccount, error) {
+	account, err := scanUserAccount(r.DB.QueryRowContext(ctx, userAccountColumns+" JOIN wallets w ON w.user_id = u.id WHERE w.wallet_number = $1", walletNumber))
+	if err == sql.ErrNoRows {
+		return nil, ErrWalletNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.withPockets(ctx, account)
+}
+
+func (r *adminRepositoryPostgres) withPockets(ctx context.Context, account *model.UserAccount) (*model.UserAccount, error) {
+	pockets, err := listPockets(ctx, r.DB, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	account.Pockets = pockets
+	return account, nil
+}
+
+// SetFrozen freezes the user with reason, or unfreezes them when reason is nil.
+func (r *adminRepositoryPostgres) SetFrozen(ctx context.Context, userID int, reason *string) error {
+	query := "UPDATE users SET frozen_at = NOW(), frozen_reason = $2, updated_at = NOW() WHERE id = $1"
+	args := []interface{}{userID, reason}
+	if reason == nil {
+		query = "UPDATE users SET frozen_at = NULL, frozen_reason = NULL, updated_at = NOW() WHERE id = $1"
+		args = args[:1]
+	}
+
+	res, err := r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	return expectOneRow(res, ErrUserNotFound)
+}
+
+func (r *adminRepositoryPostgres) SetRole(ctx context.Context, userID int, role string) error {
+	res, err := r.DB.ExecContext(ctx, "UPDATE users SET role = $2, updated_at = NOW() WHERE id = $1", userID, role)
+	if err != nil {
+		return err
+	}
+	return expectOneRow(res, ErrUserNotFound)
+}
+
+// SetWalletStatus sets the status of all walletIDs or none. It only refuses to reopen a closed wallet
+// and to close one that still holds money (read under the row lock, so a top-up cannot slip in);
+// the rules for who may set what are in the usecase.
+func (r *adminRepositoryPostgres) SetWalletStatus(ctx context.Context, walletIDs []int, status, reason string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE wallets SET status = $2, status_reason = $3, status_changed_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`
+	for _, walletID := range walletIDs {
+		var current string
+		var balance model.Money
+		err := tx.QueryRowContext(ctx, "SELECT status, balance FROM wallets WHERE id = $1 FOR UPDATE", walletID).Scan(&current, &balance)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrWalletNotFound
+			}
+			return err
+		}
+		if current == model.WalletClosed {
+			return ErrWalletClosed
+		}
+		if status == model.WalletClosed && !balance.IsZero() {
+			return ErrWalletNotEmpty
+		}
+
+		if _, err := tx.ExecContext(ctx, query, walletID, status, reason); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUserAccount(row rowScanner) (*model.UserAccount, error) {
+	var a model.UserAccount
+	var walletID sql.NullInt64
+	var balance model.Money
+	var walletNumber sql.NullString
+	var walletStatus sql.NullString
+	var walletCreatedAt sql.NullTime
+
+	err := row.Scan(&a.ID, &a.Name, &a.Email, &a.Role, &a.EmailVerified, &a.TOTPEnabled,
+		&a.FrozenAt, &a.FrozenReason, &a.CreatedAt,
+		&walletID, &balance, &walletNumber, &walletStatus, &walletCreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if walletID.Valid {
+		a.Wallet = &model.Wallet{
+			ID:           int(walletID.Int64),
+			UserID:       a.ID,
+			Balance:      balance,
+			WalletNumber: walletNumber.String,
+			Status:       walletStatus.String,
+			CreatedAt:    walletCreatedAt.Time,
+		}
+	}
+	return &a, nil
+}
+
+func expectOneRow(res sql.Result, notFound error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"ewallet-service/internal/model"
+)
+
+type AuditRepository interface {
+	Record(ctx context.Context, entry *model.AuditEntry) error
+}
+
+type auditRepositoryPostgres struct {
+	DB *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &auditRepositoryPostgres{DB: db}
+}
+
+func (r *auditRepositoryPostgres) Record(ctx context.Context, entry *model.AuditEntry) error {
+	query := `
+		INSERT INTO audit_logs (event, actor, subject, detail, ip, created_at) VALUES ($1, $2, $3, $4, NULLIF($5, ''), NOW())
+		RETURNING id, created_at
+	`
+	return r.DB.QueryRowContext(ctx, query, entry.Event, entry.Actor, entry.Subject, entry.Detail, entry.IP).Scan(&entry.ID, &entry.CreatedAt)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// errors shared by several repositories; the handler maps each of them to an HTTP status and code
+var (
+	ErrUserNotFound      = errors.New("User tidak ditemukan")
+	ErrWalletNotFound    = errors.New("Wallet tidak ditemukan")
+	ErrEmailTaken        = errors.New("Email sudah terdaftar")
+	ErrInsufficientFunds = errors.New("Saldo tidak mencukupi")
+	ErrRecipientNotFound = errors.New("Nomor wallet tujuan tidak ditemukan")
+	ErrSelfTransfer      = errors.New("Wallet asal dan tujuan tidak boleh sama")
+	ErrPINLocked         = errors.New("PIN transaksi terkunci karena terlalu banyak percobaan, coba lagi nanti")
+	ErrOTPLocked         = errors.New("Kode OTP terkunci karena terlalu banyak percobaan, coba lagi nanti")
+)
+
+// isUniqueViolation reports whether err is Postgres' unique_violation on the given constraint.
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == constraint
+}
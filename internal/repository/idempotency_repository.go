@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"ewallet-service/internal/model"
+	"time"
+)
+
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, record *model.IdempotencyRecord) (bool, error)
+	Find(ctx context.Context, userID int, endpoint, key string) (*model.IdempotencyRecord, error)
+	Complete(ctx context.Context, id int, status int, body []byte) error
+	Release(ctx context.Context, id int) error
+	PurgeOlderThan(ctx context.Context, before time.Time) (int64, error)
+}
+
+type idempotencyRepositoryPostgres struct {
+	DB *sql.DB
+}
+
+func NewIdempotencyRepository(db *sql.DB) IdempotencyRepository {
+	return &idempotencyRepositoryPostgres{DB: db}
+}
+
+// Reserve claims the key for this request. It returns false when the key is already taken
+// (by a finished request or one still in flight), relying on the UNIQUE constraint so two
+// concurrent retries can never both pass.
+func (r *idempotencyRepositoryPostgres) Reserve(ctx context.Context, record *model.IdempotencyRecord) (bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (user_id, idem_key, endpoint, request_hash, created_at) VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (user_id, endpoint, idem_key) DO NOTHING
+		RETURNING id, created_at
+	`
+
+	err := r.DB.QueryRowContext(ctx, query, record.UserID, record.Key, record.Endpoint, record.RequestHash).Scan(&record.ID, &record.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *idempotencyRepositoryPostgres) Find(ctx context.Context, userID int, endpoint, key string) (*model.IdempotencyRecord, error) {
+	query := `
+		SELECT id, user_id, idem_key, endpoint, request_hash, response_status, COALESCE(response_body::text, ''), created_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND endpoint = $2 AND idem_key = $3
+	`
+
+	var rec model.IdempotencyRecord
+	var body string
+	err := r.DB.QueryRowContext(ctx, query, userID, endpoint, key).Scan(
+		&rec.ID, &rec.UserID, &rec.Key, &rec.Endpoint, &rec.RequestHash, &rec.ResponseStatus, &body, &rec.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rec.ResponseBody = []byte(body)
+	return &rec, nil
+}
+
+func (r *idempotencyRepositoryPostgres) Complete(ctx context.Context, id int, status int, body []byte) error {
+	query := "UPDATE idempotency_keys SET response_status = $1, response_body = $2 WHERE id = $3"
+	_, err := r.DB.ExecContext(ctx, query, status, string(body), id)
+	return err
+}
+
+func (r *idempotencyRepositoryPostgres) Release(ctx context.Context, id int) error {
+	_, err := r.DB.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE id = $1", id)
+	return err
+}
+
+// PurgeOlderThan deletes the keys created before `before` and returns how many went.
+func (r *idempotencyRepositoryPostgres) PurgeOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.DB.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE created_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
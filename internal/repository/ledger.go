@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"ewallet-service/internal/model"
+	"fmt"
+)
+
+var ErrLedgerMismatch = errors.New("Jurnal tidak seimbang setelah dicatat")
+
+// The helpers below run inside the caller's *sql.Tx so a journal entry, the cached
+// wallet balances and the transaction history are committed (or rolled back) together.
+
+// systemAccountID returns the id of a SYSTEM:* ledger account seeded by database.sql.
+func systemAccountID(ctx context.Context, tx *sql.Tx, code string) (int, error) {
+	var id int
+	err := tx.QueryRowContext(ctx, "SELECT id FROM ledger_accounts WHERE code = $1 AND account_type = 'SYSTEM'", code).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("Akun ledger %s belum dibuat", code)
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// walletAccountID returns the ledger account of a wallet, creating it on first use.
+// The wallet row must already be locked FOR UPDATE by the caller and currentBalance must be
+// its balance: a wallet that holds money from before the ledger existed gets an opening entry
+// from the suspense account, so its postings always add up to wallets.balance.
+func walletAccountID(ctx context.Context, tx *sql.Tx, walletID int, currentBalance model.Money) (int, error) {
+	var id int
+	err := tx.QueryRowContext(ctx, "SELECT id FROM ledger_accounts WHERE wallet_id = $1", walletID).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	queryAccount := "INSERT INTO ledger_accounts (code, account_type, wallet_id) VALUES ($1, $2, $3) RETURNING id"
+	code := fmt.Sprintf("WALLET:%d", walletID)
+	if err := tx.QueryRowContext(ctx, queryAccount, code, model.AccountTypeWallet, walletID).Scan(&id); err != nil {
+		return 0, fmt.Errorf("Gagal membuat akun ledger wallet: %w", err)
+	}
+
+	if currentBalance.IsZero() {
+		return id, nil
+	}
+
+	suspenseID, err := systemAccountID(ctx, tx, model.AccountSuspense)
+	if err != nil {
+		return 0, err
+	}
+
+	// the balance is already in wallets.balance, so only the journal is written
+	opening := &model.JournalEntry{
+		EntryType:   model.JournalOpening,
+		Description: "Saldo awal wallet",
+		Postings: []model.Posting{
+			{AccountID: suspenseID, Amount: currentBalance.Neg()},
+			{AccountID: id, Amount: currentBalance},
+		},
+	}
+	if err := insertJournal(ctx, tx, opening); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// postJournal writes a balanced journal entry and applies its postings to the cached balance of
+// every wallet involved. Only the new entry is checked here: its stored postings must add up to
+// zero. Comparing wallets.balance with the full posting history of each account is left to
+// cmd/reconcile, so a movement does not get slower as the ledger grows.
+func postJournal(ctx context.Context, tx *sql.Tx, entry *model.JournalEntry) error {
+	if err := insertJournal(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	queryApply := `
+		UPDATE wallets SET balance = balance + $1, updated_at = NOW()
+		WHERE id = (SELECT wallet_id FROM ledger_accounts WHERE id = $2)
+	`
+	for _, p := range entry.Postings {
+		if _, err := tx.ExecContext(ctx, queryApply, p.Amount, p.AccountID); err != nil {
+			return fmt.Errorf("Gagal update saldo: %w", err)
+		}
+	}
+
+	queryVerify := "SELECT COALESCE(SUM(amount), 0), COUNT(*) FROM postings WHERE journal_entry_id = $1"
+	var sum model.Money
+	var count int
+	if err := tx.QueryRowContext(ctx, queryVerify, entry.ID).Scan(&sum, &count); err != nil {
+		return err
+	}
+	if !sum.IsZero() || count != len(entry.Postings) {
+		return ErrLedgerMismatch
+	}
+
+	return nil
+}
+
+func insertJournal(ctx context.Context, tx *sql.Tx, entry *model.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+
+	queryEntry := `
+		INSERT INTO journal_entries (entry_type, reference, description, created_at) VALUES ($1, NULLIF($2, ''), $3, NOW())
+		RETURNING id, created_at
+	`
+	err := tx.QueryRowContext(ctx, queryEntry, entry.EntryType, entry.Reference, entry.Description).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("Gagal catat jurnal: %w", err)
+	}
+
+	queryPosting := "INSERT INTO postings (journal_entry_id, account_id, amount, created_at) VALUES ($1, $2, $3, $4)"
+	for _, p := range entry.Postings {
+		if _, err := tx.ExecContext(ctx, queryPosting, entry.ID, p.AccountID, p.Amount, entry.CreatedAt); err != nil {
+			return fmt.Errorf("Gagal catat posting: %w", err)
+		}
+	}
+	return nil
+}
+
+// addFeePostings charges fee from the paying wallet's ledger account to SYSTEM:FEE_INCOME
+// within the same journal entry as the movement it belongs to. A zero fee adds nothing.
+func addFeePostings(ctx context.Context, tx *sql.Tx, entry *model.JournalEntry, payerAccount int, fee model.Money) error {
+	if fee.IsZero() {
+		return nil
+	}
+	feeAccount, err := systemAccountID(ctx, tx, model.AccountFeeIncome)
+	if err != nil {
+		return err
+	}
+	entry.Postings = append(entry.Postings,
+		model.Posting{AccountID: payerAccount, Amount: fee.Neg()},
+		model.Posting{AccountID: feeAccount, Amount: fee},
+	)
+	return nil
+}
+
+// insertFeeRow records a charged fee in the payer's history, next to the row of the movement itself.
+func insertFeeRow(ctx context.Context, tx *sql.Tx, walletID int, fee, balanceAfter model.Money, description string, transferID *int, entry *model.JournalEntry) error {
+	query := `
+		INSERT INTO transactions (wallet_id, transaction_type, amount, balance_after, description, transfer_id, journal_entry_id, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := tx.ExecContext(ctx, query, walletID, model.TransactionTypeFee, fee, balanceAfter, description, transferID, entry.ID, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("Gagal catat biaya: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"ewallet-service/internal/model"
+	"fmt"
+	"time"
+)
+
+// queryLimitUsage sums and counts the history of all pockets of one user since the start of the
+// month; the daily sums are the part from dayStart on. The user is picked by the condition on w.
+// Transfers between two pockets of the same user are left out: they are neither limited nor charged.
+const queryLimitUsage = `
+	SELECT u.email_verified_at IS NOT NULL,
+		COALESCE(SUM(t.amount) FILTER (WHERE t.transaction_type = 'TOPUP' AND t.created_at >= $2), 0),
+		COALESCE(SUM(t.amount) FILTER (WHERE t.transaction_type = 'TOPUP'), 0),
+		COUNT(t.id) FILTER (WHERE t.transaction_type = 'TOPUP'),
+		COALESCE(SUM(t.amount) FILTER (WHERE t.transaction_type = 'TRANSFER_OUT' AND t.created_at >= $2), 0),
+		COALESCE(SUM(t.amount) FILTER (WHERE t.transaction_type = 'TRANSFER_OUT'), 0),
+		COUNT(t.id) FILTER (WHERE t.transaction_type = 'TRANSFER_OUT'),
+		COALESCE(SUM(t.amount) FILTER (WHERE t.transaction_type = 'TRANSFER_IN' AND t.created_at >= $2), 0),
+		COALESCE(SUM(t.amount) FILTER (WHERE t.transaction_type = 'TRANSFER_IN'), 0),
+		COUNT(t.id) FILTER (WHERE t.transaction_type = 'TRANSFER_IN')
+	FROM wallets w
+	JOIN users u ON u.id = w.user_id
+	LEFT JOIN transactions t ON t.wallet_id = w.id AND t.created_at >= $3 AND NOT EXISTS (
+		SELECT 1 FROM transfers tf
+		JOIN wallets sw ON sw.id = tf.sender_wallet_id
+		JOIN wallets rw ON rw.id = tf.receiver_wallet_id
+		WHERE tf.id = t.transfer_id AND sw.user_id = rw.user_id
+	)
+	WHERE %s
+	GROUP BY u.id
+`
+
+// ErrLimitUsageChanged means another transaction of the same user committed while this one was being
+// checked; the caller checks again against the new usage.
+var ErrLimitUsageChanged = errors.New("Transaksi lain sedang diproses, silakan coba lagi")
+
+// GetLimitUsage aggregates what the user's pockets topped up, sent and received this day and month.
+func (r *transactionRepositoryPostgres) GetLimitUsage(ctx context.Context, userID int, dayStart, monthStart time.Time) (model.LimitUsage, error) {
+	return userLimitUsage(ctx, r.DB, userID, dayStart, monthStart)
+}
+
+// GetLimitUsageByWalletNumber is GetLimitUsage for the owner of the wallet a transfer credits.
+func (r *transactionRepositoryPostgres) GetLimitUsageByWalletNumber(ctx context.Context, walletNumber string, dayStart, monthStart time.Time) (model.LimitUsage, error) {
+	return recipientLimitUsage(ctx, r.DB, walletNumber, dayStart, monthStart)
+}
+
+func userLimitUsage(ctx context.Context, q queryRower, userID int, dayStart, monthStart time.Time) (model.LimitUsage, error) {
+	usage, err := limitUsage(ctx, q, "w.user_id = $1", userID, dayStart, monthStart)
+	if err == sql.ErrNoRows {
+		return model.LimitUsage{}, ErrWalletNotFound
+	}
+	return usage, err
+}
+
+func recipientLimitUsage(ctx context.Context, q queryRower, walletNumber string, dayStart, monthStart time.Time) (model.LimitUsage, error) {
+	usage, err := limitUsage(ctx, q, "w.user_id = (SELECT user_id FROM wallets WHERE wallet_number = $1)", walletNumber, dayStart, monthStart)
+	if err == sql.ErrNoRows {
+		return model.LimitUsage{}, ErrRecipientNotFound
+	}
+	return usage, err
+}
+
+func limitUsage(ctx context.Context, q queryRower, condition string, arg interface{}, dayStart, monthStart time.Time) (model.LimitUsage, error) {
+	var usage model.LimitUsage
+	var emailVerified bool
+
+	err := q.QueryRowContext(ctx, fmt.Sprintf(queryLimitUsage, condition), arg, dayStart, monthStart).Scan(
+		&emailVerified,
+		&usage.TopUp.Daily, &usage.TopUp.Monthly, &usage.TopUp.MonthlyCount,
+		&usage.TransferOut.Daily, &usage.TransferOut.Monthly, &usage.TransferOut.MonthlyCount,
+		&usage.TransferIn.Daily, &usage.TransferIn.Monthly, &usage.TransferIn.MonthlyCount,
+	)
+	if err != nil {
+		return model.LimitUsage{}, err
+	}
+	usage.Tier = model.TierFor(emailVerified)
+	return usage, nil
+}
+
+// lockUsage locks the user rows of the sender and of the owner of walletNumber (empty for top-ups), in id
+// order so two transfers between the same users cannot deadlock. With both locked no other top-up or
+// transfer of theirs can commit, so usage read now stays valid until this transaction ends; it must
+// still be what check was done against.
+func lockUsage(ctx context.Context, tx *sql.Tx, userID int, walletNumber string, check model.UsageCheck) error {
+	if check.Sender == nil && check.Recipient == nil {
+		return nil
+	}
+
+	queryLock := `
+		SELECT id FROM users
+		WHERE id = $1 OR id = (SELECT user_id FROM wallets WHERE wallet_number = $2)
+		ORDER BY id
+		FOR UPDATE
+	`
+	if _, err := tx.ExecContext(ctx, queryLock, userID, walletNumber); err != nil {
+		return err
+	}
+
+	if check.Sender != nil {
+		usage, err := userLimitUsage(ctx, tx, userID, check.DayStart, check.MonthStart)
+		if err != nil {
+			return err
+		}
+		if !usage.Equal(*check.Sender) {
+			return ErrLimitUsageChanged
+		}
+	}
+	if check.Recipient != nil {
+		usage, err := recipientLimitUsage(ctx, tx, walletNumber, check.DayStart, check.MonthStart)
+		if err != nil {
+			return err
+		}
+		if !usage.Equal(*check.Recipient) {
+			return ErrLimitUsageChanged
+		}
+	}
+	return nil
+}
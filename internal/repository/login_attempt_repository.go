@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"ewallet-service/internal/model"
+	"sync"
+	"time"
+)
+
+// LoginAttemptRepository stores failed login counters. The Postgres store is shared by every
+// API instance; the in-memory store is for tests and single-instance setups.
+type LoginAttemptRepository interface {
+	Get(ctx context.Context, key string) (model.LoginAttempt, error)
+	// RecordFailure adds one failure. Failures older than window are forgotten first.
+	RecordFailure(ctx context.Context, key string, window time.Duration) (model.LoginAttempt, error)
+	Lock(ctx context.Context, key string, lockFor time.Duration) (time.Time, error)
+	Reset(ctx context.Context, key string) error
+	// Purge deletes the counters whose window has passed and that are not locked.
+	Purge(ctx context.Context, window time.Duration) (int64, error)
+}
+
+type loginAttemptRepositoryPostgres struct {
+	DB *sql.DB
+}
+
+func NewLoginAttemptRepository(db *sql.DB) LoginAttemptRepository {
+	return &loginAttemptRepositoryPostgres{DB: db}
+}
+
+func (r *loginAttemptRepositoryPostgres) Get(ctx context.Context, key string) (model.LoginAttempt, error) {
+	query := "SELECT attempt_key, failures, last_failure_at, locked_until FROM login_attempts WHERE attempt_key = $1"
+
+	var a model.LoginAttempt
+	err := r.DB.QueryRowContext(ctx, query, key).Scan(&a.Key, &a.Failures, &a.LastFailureAt, &a.LockedUntil)
+	if err == sql.ErrNoRows {
+		return model.LoginAttempt{Key: key}, nil
+	}
+	return a, err
+}
+
+// RecordFailure is a single upsert, so parallel attempts are all counted.
+func (r *loginAttemptRepositoryPostgres) RecordFailure(ctx context.Context, key string, window time.Duration) (model.LoginAttempt, error) {
+	query := `
+		INSERT INTO login_attempts (attempt_key, failures, last_failure_at) VALUES ($1, 1, NOW())
+		ON CONFLICT (attempt_key) DO UPDATE SET
+			failures = CASE WHEN login_attempts.last_failure_at < NOW() - make_interval(secs => $2) THEN 1 ELSE login_attempts.failures + 1 END,
+			last_failure_at = NOW()
+		RETURNING attempt_key, failures, last_failure_at, locked_until
+	`
+
+	var a model.LoginAttempt
+	err := r.DB.QueryRowContext(ctx, query, key, window.Seconds()).Scan(&a.Key, &a.Failures, &a.LastFailureAt, &a.LockedUntil)
+	return a, err
+}
+
+func (r *loginAttemptRepositoryPostgres) Lock(ctx context.Context, key string, lockFor time.Duration) (time.Time, error) {
+	query := "UPDATE login_attempts SET locked_until = NOW() + make_interval(secs => $2) WHERE attempt_key = $1 RETURNING locked_until"
+
+	var until time.Time
+	err := r.DB.QueryRowContext(ctx, query, key, lockFor.Seconds()).Scan(&until)
+	return until, err
+}
+
+func (r *loginAttemptRepositoryPostgres) Reset(ctx context.Context, key string) error {
+	_, err := r.DB.ExecContext(ctx, "DELETE FROM login_attempts WHERE attempt_key = $1", key)
+	return err
+}
+
+func (r *loginAttemptRepositoryPostgres) Purge(ctx context.Context, window time.Duration) (int64, error) {
+	query := `
+		DELETE FROM login_attempts
+		WHERE last_failure_at < NOW() - make_interval(secs => $1) AND (locked_until IS NULL OR locked_until < NOW())
+	`
+	res, err := r.DB.ExecContext(ctx, query, window.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+type loginAttemptRepositoryMemory struct {
+	mu       sync.Mutex
+	attempts map[string]model.LoginAttempt
+}
+
+func NewMemoryLoginAttemptRepository() LoginAttemptRepository {
+	return &loginAttemptRepositoryMemory{attempts: make(map[string]model.LoginAttempt)}
+}
+
+func (r *loginAttemptRepositoryMemory) Get(ctx context.Context, key string) (model.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if a, ok := r.attempts[key]; ok {
+		return a, nil
+	}
+	return model.LoginAttempt{Key: key}, nil
+}
+
+func (r *loginAttemptRepositoryMemory) RecordFailure(ctx context.Context, key string, window time.Duration) (model.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	a, ok := r.attempts[key]
+	if !ok || a.LastFailureAt.Before(now.Add(-window)) {
+		a = model.LoginAttempt{Key: key, LockedUntil: a.LockedUntil}
+	}
+	a.Failures++
+	a.LastFailureAt = now
+	r.attempts[key] = a
+	return a, nil
+}
+
+func (r *loginAttemptRepositoryMemory) Lock(ctx context.Context, key string, lockFor time.Duration) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.attempts[key]
+	if !ok {
+		return time.Time{}, sql.ErrNoRows
+	}
+	until := time.Now().Add(lockFor)
+	a.LockedUntil = &until
+	r.attempts[key] = a
+	return until, nil
+}
+
+func (r *loginAttemptRepositoryMemory) Reset(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+	return nil
+}
+
+func (r *loginAttemptRepositoryMemory) Purge(ctx context.Context, window time.Duration) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	var purged int64
+	for key, a := range r.attempts {
+		if a.LastFailureAt.Before(now.Add(-window)) && !a.Locked(now) {
+			delete(r.attempts, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"context"
+	"ewallet-service/internal/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ActionTokenRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ActionTokenRepositoryMock) Create(ctx context.Context, token *model.ActionToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *ActionTokenRepositoryMock) Consume(ctx context.Context, jti, purpose string) (int, error) {
+	args := m.Called(ctx, jti, purpose)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *ActionTokenRepositoryMock) InvalidateUnused(ctx context.Context, userID int, purpose string) error {
+	args := m.Called(ctx, userID, purpose)
+	return args.Error(0)
+}
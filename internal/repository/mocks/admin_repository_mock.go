@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"context"
+	"ewallet-service/internal/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type AdminRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *AdminRepositoryMock) SearchUsers(ctx context.Context, search model.UserSearch) ([]model.UserAccount, error) {
+	args := m.Called(ctx, search)
+	return args.Get(0).([]model.UserAccount), args.Error(1)
+}
+
+func (m *AdminRepositoryMock) FindUserAccount(ctx context.Context, userID int) (*model.UserAccount, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserAccount), args.Error(1)
+}
+
+func (m *AdminRepositoryMock) FindAccountByWalletNumber(ctx context.Context, walletNumber string) (*model.UserAccount, error) {
+	args := m.Called(ctx, walletNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserAccount), args.Error(1)
+}
+
+func (m *AdminRepositoryMock) SetFrozen(ctx context.Context, userID int, reason *string) error {
+	args := m.Called(ctx, userID, reason)
+	return args.Error(0)
+}
+
+func (m *AdminRepositoryMock) SetRole(ctx context.Context, userID int, role string) error {
+	args := m.Called(ctx, userID, role)
+	return args.Error(0)
+}
+
+func (m *AdminRepositoryMock) SetWalletStatus(ctx context.Context, walletIDs []int, status, reason string) error {
+	args := m.Called(ctx, walletIDs, status, reason)
+	return args.Error(0)
+}
@@ -0,0 +1,17 @@
+package mocks
+
+import (
+	"context"
+	"ewallet-service/internal/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type AuditRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *AuditRepositoryMock) Record(ctx context.Context, entry *model.AuditEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
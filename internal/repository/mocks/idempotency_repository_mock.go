@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"context"
+	"ewallet-service/internal/model"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type IdempotencyRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *IdempotencyRepositoryMock) Reserve(ctx context.Context, record *model.IdempotencyRecord) (bool, error) {
+	args := m.Called(ctx, record)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *IdempotencyRepositoryMock) Find(ctx context.Context, userID int, endpoint, key string) (*model.IdempotencyRecord, error) {
+	args := m.Called(ctx, userID, endpoint, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.IdempotencyRecord), args.Error(1)
+}
+
+func (m *IdempotencyRepositoryMock) Complete(ctx context.Context, id int, status int, body []byte) error {
+	args := m.Called(ctx, id, status, body)
+	return args.Error(0)
+}
+
+func (m *IdempotencyRepositoryMock) Release(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *IdempotencyRepositoryMock) PurgeOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"context"
+	"ewallet-service/internal/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PocketRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *PocketRepositoryMock) ListPockets(ctx context.Context, userID int) ([]model.Wallet, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Wallet), args.Error(1)
+}
+
+func (m *PocketRepositoryMock) CreatePocket(ctx context.Context, userID int, name string) (model.Wallet, error) {
+	args := m.Called(ctx, userID, name)
+	return args.Get(0).(model.Wallet), args.Error(1)
+}
@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"context"
+	"ewallet-service/internal/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ReconcileRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ReconcileRepositoryMock) ListWalletReconciliations(ctx context.Context) ([]model.WalletReconciliation, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.WalletReconciliation), args.Error(1)
+}
+
+func (m *ReconcileRepositoryMock) AdjustHistory(ctx context.Context, walletID int) (model.Money, error) {
+	args := m.Called(ctx, walletID)
+	return args.Get(0).(model.Money), args.Error(1)
+}
@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"context"
+	"ewallet-service/internal/model"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SessionRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *SessionRepositoryMock) CreateSession(ctx context.Context, session *model.Session, refreshTokenHash string) error {
+	args := m.Called(ctx, session, refreshTokenHash)
+	return args.Error(0)
+}
+
+func (m *SessionRepositoryMock) RotateRefreshToken(ctx context.Context, oldHash, newHash string) (*model.Session, error) {
+	args := m.Called(ctx, oldHash, newHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Session), args.Error(1)
+}
+
+func (m *SessionRepositoryMock) RevokeSession(ctx context.Context, sessionID string) error {
+	args := m.Called(ctx, sessionID)
+	return args.Error(0)
+}
+
+func (m *SessionRepositoryMock) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	args := m.Called(ctx, jti, expiresAt)
+	return args.Error(0)
+}
+
+func (m *SessionRepositoryMock) ConsumeToken(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	args := m.Called(ctx, jti, expiresAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *SessionRepositoryMock) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	args := m.Called(ctx, jti)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *SessionRepositoryMock) RevokeUserSessions(ctx context.Context, userID int) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *SessionRepositoryMock) IsSessionRevoked(ctx context.Context, sessionID string) (bool, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *SessionRepositoryMock) PurgeRevokedTokens(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
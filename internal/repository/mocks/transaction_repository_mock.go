@@ -3,6 +3,7 @@ package mocks
 import (
 	"context"
 	"ewallet-service/internal/model"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -11,17 +12,80 @@ type TransactionRepositoryMock struct {
 	mock.Mock
 }
 
-func (m *TransactionRepositoryMock) CreateTopUp(ctx context.Context, userID int, amount float64) (model.TopUpResponse, error) {
-	args := m.Called(ctx, userID, amount)
+func (m *TransactionRepositoryMock) CreateTopUp(ctx context.Context, userID int, amount, fee model.Money, check model.UsageCheck) (model.TopUpResponse, error) {
+	args := m.Called(ctx, userID, amount, fee, check)
 	return args.Get(0).(model.TopUpResponse), args.Error(1)
 }
 
-func (m *TransactionRepositoryMock) Transfer(ctx context.Context, senderID int, req model.TransferRequest) (model.TransferResponse, error) {
-	args := m.Called(ctx, senderID, req)
+func (m *TransactionRepositoryMock) Transfer(ctx context.Context, senderID int, req model.TransferRequest, fee model.Money, check model.UsageCheck) (model.TransferResponse, error) {
+	args := m.Called(ctx, senderID, req, fee, check)
 	return args.Get(0).(model.TransferResponse), args.Error(1)
 }
 
-func (m *TransactionRepositoryMock) GetTransactionHistory(ctx context.Context, userID int) ([]model.Transaction, error) {
-	args := m.Called(ctx, userID)
+func (m *TransactionRepositoryMock) GetTransactionHistory(ctx context.Context, userID int, filter model.TransactionFilter) ([]model.Transaction, error) {
+	args := m.Called(ctx, userID, filter)
 	return args.Get(0).([]model.Transaction), args.Error(1)
 }
+
+func (m *TransactionRepositoryMock) FindTransferByReference(ctx context.Context, userID int, reference string) (*model.Transfer, error) {
+	args := m.Called(ctx, userID, reference)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Transfer), args.Error(1)
+}
+
+func (m *TransactionRepositoryMock) FindTransactionByID(ctx context.Context, userID int, id int) (*model.TransactionDetail, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TransactionDetail), args.Error(1)
+}
+
+func (m *TransactionRepositoryMock) GetStatement(ctx context.Context, userID int, walletNumber string, from, to time.Time) (*model.Statement, []model.Transaction, error) {
+	args := m.Called(ctx, userID, walletNumber, from, to)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*model.Statement), args.Get(1).([]model.Transaction), args.Error(2)
+}
+
+func (m *TransactionRepositoryMock) GetLimitUsage(ctx context.Context, userID int, dayStart, monthStart time.Time) (model.LimitUsage, error) {
+	args := m.Called(ctx, userID, dayStart, monthStart)
+	return args.Get(0).(model.LimitUsage), args.Error(1)
+}
+
+func (m *TransactionRepositoryMock) GetLimitUsageByWalletNumber(ctx context.Context, walletNumber string, dayStart, monthStart time.Time) (model.LimitUsage, error) {
+	args := m.Called(ctx, walletNumber, dayStart, monthStart)
+	return args.Get(0).(model.LimitUsage), args.Error(1)
+}
+
+func (m *TransactionRepositoryMock) FindRecipient(ctx context.Context, senderID int, sourceWalletNumber, walletNumber string) (*model.Recipient, error) {
+	args := m.Called(ctx, senderID, sourceWalletNumber, walletNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Recipient), args.Error(1)
+}
+
+func (m *TransactionRepositoryMock) CreateTransferQuote(ctx context.Context, quote *model.TransferQuote) error {
+	args := m.Called(ctx, quote)
+	return args.Error(0)
+}
+
+func (m *TransactionRepositoryMock) FindTransferQuote(ctx context.Context, userID int, id string) (*model.TransferQuote, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TransferQuote), args.Error(1)
+}
+
+func (m *TransactionRepositoryMock) FindWalletByNumber(ctx context.Context, walletNumber string) (*model.Recipient, error) {
+	args := m.Called(ctx, walletNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Recipient), args.Error(1)
+}
@@ -3,6 +3,7 @@ package mocks
 import (
 	"context"
 	"ewallet-service/internal/model"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -36,3 +37,93 @@ func (m *UserRepositoryMock) FindWalletByUserID(ctx context.Context, userID int)
 	}
 	return args.Get(0).(*model.Wallet), args.Error(1)
 }
+
+func (m *UserRepositoryMock) FindByID(ctx context.Context, userID int) (*model.User, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (m *UserRepositoryMock) GetPINState(ctx context.Context, userID int) (*model.PINState, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PINState), args.Error(1)
+}
+
+func (m *UserRepositoryMock) SetPIN(ctx context.Context, userID int, pinHash string) error {
+	args := m.Called(ctx, userID, pinHash)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) ClaimPINAttempt(ctx context.Context, userID int, maxAttempts int, lockFor time.Duration) (*model.PINState, error) {
+	args := m.Called(ctx, userID, maxAttempts, lockFor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PINState), args.Error(1)
+}
+
+func (m *UserRepositoryMock) ResetPINFailures(ctx context.Context, userID int) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) GetTOTPState(ctx context.Context, userID int) (*model.TOTPState, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TOTPState), args.Error(1)
+}
+
+func (m *UserRepositoryMock) SetTOTPSecret(ctx context.Context, userID int, secret string) error {
+	args := m.Called(ctx, userID, secret)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) EnableTOTP(ctx context.Context, userID int, recoveryCodeHashes []string) error {
+	args := m.Called(ctx, userID, recoveryCodeHashes)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) DisableTOTP(ctx context.Context, userID int) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) ClaimOTPAttempt(ctx context.Context, userID int, maxAttempts int, lockFor time.Duration) (*time.Time, error) {
+	args := m.Called(ctx, userID, maxAttempts, lockFor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *UserRepositoryMock) ResetOTPFailures(ctx context.Context, userID int) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) MarkTOTPStepUsed(ctx context.Context, userID int, step int64) (bool, error) {
+	args := m.Called(ctx, userID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *UserRepositoryMock) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	args := m.Called(ctx, userID, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *UserRepositoryMock) MarkEmailVerified(ctx context.Context, userID int) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) UpdatePassword(ctx context.Context, userID int, passwordHash string) error {
+	args := m.Called(ctx, userID, passwordHash)
+	return args.Error(0)
+}
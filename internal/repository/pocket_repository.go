@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"ewallet-service/internal/model"
+)
+
+var (
+	ErrPocketNameTaken = errors.New("Nama pocket sudah dipakai")
+	ErrPocketLimit     = errors.New("Jumlah pocket sudah maksimal")
+)
+
+// PocketRepository manages the wallets of one user beyond the primary one. Money moves between
+// pockets through TransactionRepository.Transfer like any other transfer.
+type PocketRepository interface {
+	ListPockets(ctx context.Context, userID int) ([]model.Wallet, error)
+	CreatePocket(ctx context.Context, userID int, name string) (model.Wallet, error)
+}
+
+type pocketRepositoryPostgres struct {
+	DB *sql.DB
+}
+
+func NewPocketRepository(db *sql.DB) PocketRepository {
+	return &pocketRepositoryPostgres{DB: db}
+}
+
+const walletColumns = "id, user_id, name, is_primary, balance, wallet_number, status, created_at"
+
+func scanWallet(row rowScanner) (*model.Wallet, error) {
+	var w model.Wallet
+	err := row.Scan(&w.ID, &w.UserID, &w.Name, &w.IsPrimary, &w.Balance, &w.WalletNumber, &w.Status, &w.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// ListPockets returns the primary pocket first, the others in the order they were opened.
+func (r *pocketRepositoryPostgres) ListPockets(ctx context.Context, userID int) ([]model.Wallet, error) {
+	return listPockets(ctx, r.DB, userID)
+}
+
+func listPockets(ctx context.Context, db *sql.DB, userID int) ([]model.Wallet, error) {
+	query := "SELECT " + walletColumns + " FROM wallets WHERE user_id = $1 ORDER BY is_primary DESC, id"
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pockets := []model.Wallet{}
+	for rows.Next() {
+		w, err := scanWallet(rows)
+		if err != nil {
+			return nil, err
+		}
+		pockets = append(pockets, *w)
+	}
+	return pockets, rows.Err()
+}
+
+// CreatePocket opens a pocket named name. The user row is locked while the pockets are counted,
+// so parallel requests cannot open more than model.MaxPockets.
+func (r *pocketRepositoryPostgres) CreatePocket(ctx context.Context, userID int, name string) (model.Wallet, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return model.Wallet{}, err
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRowContext(ctx, "SELECT id FROM users WHERE id = $1 FOR UPDATE", userID).Scan(&userID); err != nil {
+		if err == sql.ErrNoRows {
+			return model.Wallet{}, ErrUserNotFound
+		}
+		return model.Wallet{}, err
+	}
+	var count int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM wallets WHERE user_id = $1", userID).Scan(&count); err != nil {
+		return model.Wallet{}, err
+	}
+	if count >= model.MaxPockets {
+		return model.Wallet{}, ErrPocketLimit
+	}
+
+	wallet, err := insertWallet(ctx, tx, userID, name, false)
+	if err != nil {
+		if isUniqueViolation(err, "idx_wallets_user_name") {
+			return model.Wallet{}, ErrPocketNameTaken
+		}
+		return model.Wallet{}, err
+	}
+
+	// a restriction covers all pockets of the user (see AdminUsecase.ChangeWalletStatus), new ones included
+	queryRestriction := `
+		UPDATE wallets w SET status = p.status, status_reason = p.status_reason, status_changed_at = NOW()
+		FROM wallets p
+		WHERE w.id = $1 AND p.user_id = w.user_id AND p.is_primary AND p.status IN ('frozen', 'debit_blocked', 'credit_blocked')
+		RETURNING w.status
+	`
+	err = tx.QueryRowContext(ctx, queryRestriction, wallet.ID).Scan(&wallet.Status)
+	if err != nil && err != sql.ErrNoRows {
+		return model.Wallet{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Wallet{}, err
+	}
+	return wallet, nil
+}
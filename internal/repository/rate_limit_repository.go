@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// RateLimitRepository counts requests per key in fixed windows. Like the login attempts, the
+// Postgres store is shared by every API instance and the in-memory store is for tests.
+type RateLimitRepository interface {
+	// Hit counts one request in the window starting at windowStart and returns the count so far;
+	// a new window starts again from one.
+	Hit(ctx context.Context, key string, windowStart time.Time) (int, error)
+}
+
+type rateLimitRepositoryPostgres struct {
+	DB *sql.DB
+}
+
+func NewRateLimitRepository(db *sql.DB) RateLimitRepository {
+	return &rateLimitRepositoryPostgres{DB: db}
+}
+
+// Hit is a single upsert, so parallel requests are all counted.
+func (r *rateLimitRepositoryPostgres) Hit(ctx context.Context, key string, windowStart time.Time) (int, error) {
+	query := `
+		INSERT INTO rate_limits (rate_key, window_start, hits) VALUES ($1, $2, 1)
+		ON CONFLICT (rate_key) DO UPDATE SET
+			hits = CASE WHEN rate_limits.window_start = EXCLUDED.window_start THEN rate_limits.hits + 1 ELSE 1 END,
+			window_start = EXCLUDED.window_start
+		RETURNING hits
+	`
+
+	var hits int
+	err := r.DB.QueryRowContext(ctx, query, key, windowStart).Scan(&hits)
+	return hits, err
+}
+
+type rateLimitWindow struct {
+	start time.Time
+	hits  int
+}
+
+type rateLimitRepositoryMemory struct {
+	mu      sync.Mutex
+	windows map[string]rateLimitWindow
+}
+
+func NewMemoryRateLimitRepository() RateLimitRepository {
+	return &rateLimitRepositoryMemory{windows: make(map[string]rateLimitWindow)}
+}
+
+func (r *rateLimitRepositoryMemory) Hit(ctx context.Context, key string, windowStart time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w := r.windows[key]
+	if !w.start.Equal(windowStart) {
+		w = rateLimitWindow{start: windowStart}
+	}
+	w.hits++
+	r.windows[key] = w
+	return w.hits, nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"ewallet-service/internal/model"
+	"fmt"
+)
+
+type ReconcileRepository interface {
+	ListWalletReconciliations(ctx context.Context) ([]model.WalletReconciliation, error)
+	AdjustHistory(ctx context.Context, walletID int) (model.Money, error)
+}
+
+type reconcileRepositoryPostgres struct {
+	DB *sql.DB
+}
+
+func NewReconcileRepository(db *sql.DB) ReconcileRepository {
+	return &reconcileRepositoryPostgres{DB: db}
+}
+
+// historyBalanceExpr recomputes a balance from the transactions history rows.
+const historyBalanceExpr = `
+	COALESCE(SUM(CASE t.transaction_type
+		WHEN 'TOPUP' THEN t.amount
+		WHEN 'TRANSFER_IN' THEN t.amount
+		WHEN 'TRANSFER_OUT' THEN -t.amount
+		WHEN 'FEE' THEN -t.amount
+		WHEN 'ADJUSTMENT' THEN t.amount
+		ELSE 0
+	END), 0)
+`
+
+func (r *reconcileRepositoryPostgres) ListWalletReconciliations(ctx context.Context) ([]model.WalletReconciliation, error) {
+	query := `
+		SELECT w.id, w.wallet_number, w.balance, ` + historyBalanceExpr + `, COUNT(t.id),
+			COALESCE((
+				SELECT SUM(p.amount) FROM postings p
+				JOIN ledger_accounts a ON a.id = p.account_id
+				WHERE a.wallet_id = w.id
+			), 0)
+		FROM wallets w
+		LEFT JOIN transactions t ON t.wallet_id = w.id
+		GROUP BY w.id, w.wallet_number, w.balance
+		ORDER BY w.id
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []model.WalletReconciliation
+	for rows.Next() {
+		var w model.WalletReconciliation
+		if err := rows.Scan(&w.WalletID, &w.WalletNumber, &w.Balance, &w.HistoryBalance, &w.TransactionCount, &w.LedgerBalance); err != nil {
+			return nil, err
+		}
+		w.Difference = w.Balance.Sub(w.HistoryBalance)
+		result = append(result, w)
+	}
+
+	return result, rows.Err()
+}
+
+// AdjustHistory writes one ADJUSTMENT row so the wallet's history adds up to wallets.balance.
+// The balance itself is left alone: it is backed by the ledger postings. The difference is
+// recomputed under the wallet lock so a concurrent transfer cannot be "corrected" away.
+func (r *reconcileRepositoryPostgres) AdjustHistory(ctx context.Context, walletID int) (model.Money, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return model.Money{}, err
+	}
+	defer tx.Rollback()
+
+	var balance model.Money
+	err = tx.QueryRowContext(ctx, "SELECT balance FROM wallets WHERE id = $1 FOR UPDATE", walletID).Scan(&balance)
+	if err != nil {
+		return model.Money{}, err
+	}
+
+	var history model.Money
+	query := "SELECT " + historyBalanceExpr + " FROM transactions t WHERE t.wallet_id = $1"
+	if err := tx.QueryRowContext(ctx, query, walletID).Scan(&history); err != nil {
+		return model.Money{}, err
+	}
+
+	diff := balance.Sub(history)
+	if diff.IsZero() {
+		return diff, nil
+	}
+
+	queryAdjust := `
+		INSERT INTO transactions (wallet_id, transaction_type, amount, balance_after, description, created_at) VALUES ($1, $2, $3, $4, $5, NOW())
+	`
+	_, err = tx.ExecContext(ctx, queryAdjust, walletID, model.TransactionTypeAdjustment, diff, balance, "Penyesuaian rekonsiliasi")
+	if err != nil {
+		return model.Money{}, fmt.Errorf("Gagal catat penyesuaian: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Money{}, err
+	}
+	return diff, nil
+}
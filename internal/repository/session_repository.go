@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"ewallet-service/internal/model"
+	"time"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("Refresh token tidak valid atau kadaluarsa")
+	ErrRefreshTokenReused  = errors.New("Refresh token sudah pernah dipakai, sesi dicabut")
+	ErrAccountFrozen       = errors.New("Akun dibekukan, hubungi customer service")
+)
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session *model.Session, refreshTokenHash string) error
+	RotateRefreshToken(ctx context.Context, oldHash, newHash string) (*model.Session, error)
+	RevokeSession(ctx context.Context, sessionID string) error
+	RevokeUserSessions(ctx context.Context, userID int) error
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	ConsumeToken(ctx context.Context, jti string, expiresAt time.Time) (bool, error)
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	IsSessionRevoked(ctx context.Context, sessionID string) (bool, error)
+	PurgeRevokedTokens(ctx context.Context) (int64, error)
+}
+
+type sessionRepositoryPostgres struct {
+	DB *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) SessionRepository {
+	return &sessionRepositoryPostgres{DB: db}
+}
+
+func (r *sessionRepositoryPostgres) CreateSession(ctx context.Context, session *model.Session, refreshTokenHash string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO sessions (id, user_id, expires_at) VALUES ($1, $2, $3)", session.ID, session.UserID, session.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO refresh_tokens (token_hash, session_id) VALUES ($1, $2)", refreshTokenHash, session.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RotateRefreshToken marks oldHash as used and stores newHash for the same session.
+// Presenting a token that was already rotated means it leaked: the whole session is revoked.
+func (r *sessionRepositoryPostgres) RotateRefreshToken(ctx context.Context, oldHash, newHash string) (*model.Session, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var sessionID string
+	err = tx.QueryRowContext(ctx, "UPDATE refresh_tokens SET used_at = NOW() WHERE token_hash = $1 AND used_at IS NULL RETURNING session_id", oldHash).Scan(&sessionID)
+	if err == sql.ErrNoRows {
+		err = tx.QueryRowContext(ctx, "SELECT session_id FROM refresh_tokens WHERE token_hash = $1", oldHash).Scan(&sessionID)
+		if err == sql.ErrNoRows {
+			return nil, ErrRefreshTokenInvalid
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL", sessionID); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// email and role are read fresh, so a role change applies from the next refresh
+	var s model.Session
+	var frozen bool
+	query := `
+		SELECT s.id, s.user_id, u.email, u.role, u.frozen_at IS NOT NULL, s.expires_at, s.revoked_at
+		FROM sessions s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.id = $1
+	`
+	if err := tx.QueryRowContext(ctx, query, sessionID).Scan(&s.ID, &s.UserID, &s.Email, &s.Role, &frozen, &s.ExpiresAt, &s.RevokedAt); err != nil {
+		return nil, err
+	}
+	if s.RevokedAt != nil || time.Now().After(s.ExpiresAt) {
+		return nil, ErrRefreshTokenInvalid
+	}
+	if frozen {
+		return nil, ErrAccountFrozen
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO refresh_tokens (token_hash, session_id) VALUES ($1, $2)", newHash, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *sessionRepositoryPostgres) RevokeSession(ctx context.Context, sessionID string) error {
+	_, err := r.DB.ExecContext(ctx, "UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL", sessionID)
+	return err
+}
+
+// RevokeUserSessions ends every session of a user, e.g. after a password reset.
+func (r *sessionRepositoryPostgres) RevokeUserSessions(ctx context.Context, userID int) error {
+	_, err := r.DB.ExecContext(ctx, "UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL", userID)
+	return err
+}
+
+func (r *sessionRepositoryPostgres) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	query := "INSERT INTO revoked_tokens (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING"
+	_, err := r.DB.ExecContext(ctx, query, jti, expiresAt)
+	return err
+}
+
+// ConsumeToken revokes a single-use token and reports whether this call was the one that did.
+func (r *sessionRepositoryPostgres) ConsumeToken(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	query := "INSERT INTO revoked_tokens (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING"
+	res, err := r.DB.ExecContext(ctx, query, jti, expiresAt)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (r *sessionRepositoryPostgres) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var revoked bool
+	err := r.DB.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1)", jti).Scan(&revoked)
+	return revoked, err
+}
+
+// IsSessionRevoked reports whether the session was ended (logout, freeze, password reset, ...).
+// A session that does not exist counts as revoked.
+func (r *sessionRepositoryPostgres) IsSessionRevoked(ctx context.Context, sessionID string) (bool, error) {
+	var revoked bool
+	err := r.DB.QueryRowContext(ctx, "SELECT revoked_at IS NOT NULL FROM sessions WHERE id = $1", sessionID).Scan(&revoked)
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	return revoked, err
+}
+
+// PurgeRevokedTokens deletes revoked jtis whose token has expired anyway and returns how many went.
+func (r *sessionRepositoryPostgres) PurgeRevokedTokens(ctx context.Context) (int64, error) {
+	res, err := r.DB.ExecContext(ctx, "DELETE FROM revoked_tokens WHERE expires_at < NOW()")
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
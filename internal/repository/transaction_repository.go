@@ -6,13 +6,30 @@ import (
 	"errors"
 	"ewallet-service/internal/model"
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrTransferNotFound    = errors.New("Transfer tidak ditemukan")
+	ErrTransactionNotFound = errors.New("Transaksi tidak ditemukan")
 )
 
 type TransactionRepository interface {
-	CreateTopUp(ctx context.Context, userID int, amount float64) (model.TopUpResponse, error)
-	Transfer(ctx context.Context, senderID int, req model.TransferRequest) (model.TransferResponse, error)
-	GetTransactionHistory(ctx context.Context, userID int) ([]model.Transaction, error)
+	CreateTopUp(ctx context.Context, userID int, amount, fee model.Money, check model.UsageCheck) (model.TopUpResponse, error)
+	Transfer(ctx context.Context, senderID int, req model.TransferRequest, fee model.Money, check model.UsageCheck) (model.TransferResponse, error)
+	GetTransactionHistory(ctx context.Context, userID int, filter model.TransactionFilter) ([]model.Transaction, error)
+	FindTransferByReference(ctx context.Context, userID int, reference string) (*model.Transfer, error)
+	FindTransactionByID(ctx context.Context, userID int, id int) (*model.TransactionDetail, error)
+	GetStatement(ctx context.Context, userID int, walletNumber string, from, to time.Time) (*model.Statement, []model.Transaction, error)
+	GetLimitUsage(ctx context.Context, userID int, dayStart, monthStart time.Time) (model.LimitUsage, error)
+	GetLimitUsageByWalletNumber(ctx context.Context, walletNumber string, dayStart, monthStart time.Time) (model.LimitUsage, error)
+	FindRecipient(ctx context.Context, senderID int, sourceWalletNumber, walletNumber string) (*model.Recipient, error)
+	FindWalletByNumber(ctx context.Context, walletNumber string) (*model.Recipient, error)
+	CreateTransferQuote(ctx context.Context, quote *model.TransferQuote) error
+	FindTransferQuote(ctx context.Context, userID int, id string) (*model.TransferQuote, error)
 }
 
 type transactionRepositoryPostgres struct {
@@ -23,44 +40,81 @@ func NewTransactionRepository(db *sql.DB) TransactionRepository {
 	return &transactionRepositoryPostgres{DB: db}
 }
 
-func (r *transactionRepositoryPostgres) CreateTopUp(ctx context.Context, userID int, amount float64) (model.TopUpResponse, error) {
+// CreateTopUp credits amount to the user's primary pocket and charges fee from it, so the wallet ends up
+// amount - fee richer. A non-zero fee is its own FEE history row. The top-up is refused with
+// ErrLimitUsageChanged when the user's usage is no longer check.Sender.
+func (r *transactionRepositoryPostgres) CreateTopUp(ctx context.Context, userID int, amount, fee model.Money, check model.UsageCheck) (model.TopUpResponse, error) {
 	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return model.TopUpResponse{}, err
 	}
 	defer tx.Rollback()
 
+	if err := lockUsage(ctx, tx, userID, "", check); err != nil {
+		return model.TopUpResponse{}, err
+	}
+
 	var walletID int
 	var walletNumber string
-	var currentBalance float64
+	var currentBalance model.Money
+	var status string
 
-	queryCheck := "SELECT id, wallet_number, balance FROM wallets WHERE user_id = $1 FOR UPDATE"
-	err = tx.QueryRowContext(ctx, queryCheck, userID).Scan(&walletID, &walletNumber, &currentBalance)
+	queryCheck := "SELECT id, wallet_number, balance, status FROM wallets WHERE user_id = $1 AND is_primary FOR UPDATE"
+	err = tx.QueryRowContext(ctx, queryCheck, userID).Scan(&walletID, &walletNumber, &currentBalance, &status)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return model.TopUpResponse{}, errors.New("Wallet tidak ditemukan")
+			return model.TopUpResponse{}, ErrWalletNotFound
 		}
 		return model.TopUpResponse{}, err
 	}
+	if err := checkCredit(status); err != nil {
+		return model.TopUpResponse{}, err
+	}
 
-	newBalance := currentBalance + amount
-	queryUpdate := "UPDATE wallets SET balance = $1, updated_at = NOW() WHERE id = $2"
-	_, err = tx.ExecContext(ctx, queryUpdate, newBalance, walletID)
+	walletAccount, err := walletAccountID(ctx, tx, walletID, currentBalance)
 	if err != nil {
-		return model.TopUpResponse{}, fmt.Errorf("Gagal update saldo: %w", err)
+		return model.TopUpResponse{}, err
+	}
+	fundingAccount, err := systemAccountID(ctx, tx, model.AccountTopUpFunding)
+	if err != nil {
+		return model.TopUpResponse{}, err
+	}
+
+	// money comes from the top-up funding source into the wallet
+	entry := &model.JournalEntry{
+		EntryType:   model.JournalTopUp,
+		Description: "Topup Saldo via API",
+		Postings: []model.Posting{
+			{AccountID: fundingAccount, Amount: amount.Neg()},
+			{AccountID: walletAccount, Amount: amount},
+		},
+	}
+	if err := addFeePostings(ctx, tx, entry, walletAccount, fee); err != nil {
+		return model.TopUpResponse{}, err
+	}
+	if err := postJournal(ctx, tx, entry); err != nil {
+		return model.TopUpResponse{}, err
 	}
 
 	var transactionID int
 	queryHistory := `
-		INSERT INTO transactions (wallet_id, transaction_type, amount, description, created_at) VALUES ($1, 'TOPUP', $2, 'Topup Saldo via API', NOW())
+		INSERT INTO transactions (wallet_id, transaction_type, amount, balance_after, description, journal_entry_id, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id
 	`
 
-	err = tx.QueryRowContext(ctx, queryHistory, walletID, amount).Scan(&transactionID)
+	newBalance := currentBalance.Add(amount)
+	err = tx.QueryRowContext(ctx, queryHistory, walletID, model.TransactionTypeTopUp, amount, newBalance, entry.Description, entry.ID, entry.CreatedAt).Scan(&transactionID)
 	if err != nil {
 		return model.TopUpResponse{}, fmt.Errorf("Gagal catat history: %w", err)
 	}
 
+	if !fee.IsZero() {
+		newBalance = newBalance.Sub(fee)
+		if err := insertFeeRow(ctx, tx, walletID, fee, newBalance, "Biaya topup", nil, entry); err != nil {
+			return model.TopUpResponse{}, err
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return model.TopUpResponse{}, err
 	}
@@ -71,11 +125,16 @@ func (r *transactionRepositoryPostgres) CreateTopUp(ctx context.Context, userID
 		BalanceBefore: currentBalance,
 		BalanceAfter:  newBalance,
 		TopUpAmount:   amount,
-		CreatedAt:     time.Now(),
+		Fee:           fee,
+		CreatedAt:     entry.CreatedAt,
 	}, nil
 }
 
-func (r *transactionRepositoryPostgres) Transfer(ctx context.Context, senderID int, req model.TransferRequest) (model.TransferResponse, error) {
+// Transfer moves req.Amount from the sender's pocket req.SourceWalletNumber (the primary one when
+// empty) to the recipient and charges fee to the sender on top of it. The recipient may be another
+// pocket of the sender, only not the same one. Like CreateTopUp it is refused with ErrLimitUsageChanged
+// when the usage of sender or recipient moved since check.
+func (r *transactionRepositoryPostgres) Transfer(ctx context.Context, senderID int, req model.TransferRequest, fee model.Money, check model.UsageCheck) (model.TransferResponse, error) {
 	// start transaction
 	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
@@ -83,98 +142,181 @@ func (r *transactionRepositoryPostgres) Transfer(ctx context.Context, senderID i
 	}
 	defer tx.Rollback()
 
+	if err := lockUsage(ctx, tx, senderID, req.TargetWalletNumber, check); err != nil {
+		return model.TransferResponse{}, err
+	}
+
+	if req.QuoteID != "" {
+		if err := consumeTransferQuote(ctx, tx, senderID, req.QuoteID); err != nil {
+			return model.TransferResponse{}, err
+		}
+	}
+
 	// check sender wallet & saldo (locking)
 	var senderWalletID int
-	var senderBalance float64
+	var senderWalletNumber string
+	var senderBalance model.Money
+	var senderStatus string
 
-	querySender := "SELECT id, balance FROM wallets WHERE user_id = $1 FOR UPDATE"
-	err = tx.QueryRowContext(ctx, querySender, senderID).Scan(&senderWalletID, &senderBalance)
+	querySender := "SELECT id, wallet_number, balance, status FROM wallets WHERE " + ownWalletCondition + " FOR UPDATE"
+	err = tx.QueryRowContext(ctx, querySender, senderID, req.SourceWalletNumber).Scan(&senderWalletID, &senderWalletNumber, &senderBalance, &senderStatus)
 	if err != nil {
-		return model.TransferResponse{}, errors.New("Wallet Pengirim tidak ditemukan")
+		if err == sql.ErrNoRows {
+			return model.TransferResponse{}, ErrWalletNotFound
+		}
+		return model.TransferResponse{}, err
 	}
 
-	// check the balance enough?
-	if senderBalance < req.Amount {
-		return model.TransferResponse{}, errors.New("Saldo tidak mencukupi")
+	// the status is read under the lock, so a freeze cannot slip in between check and debit
+	if err := checkDebit(senderStatus); err != nil {
+		return model.TransferResponse{}, err
 	}
 
-	// check receiver wallet (locking)
-	var receiverWalletID int
-	var receiverUserID int
+	// check the balance enough? the fee has to be covered too
+	if senderBalance.LessThan(req.Amount.Add(fee)) {
+		return model.TransferResponse{}, ErrInsufficientFunds
+	}
 
-	queryReceiver := "SELECT id, user_id FROM wallets WHERE wallet_number = $1 FOR UPDATE"
-	err = tx.QueryRowContext(ctx, queryReceiver, req.TargetWalletNumber).Scan(&receiverWalletID, &receiverUserID)
+	// check receiver wallet (locking): not the sending pocket, and able to receive
+	receiver, receiverBalance, err := findRecipient(ctx, tx, req.TargetWalletNumber, true)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return model.TransferResponse{}, errors.New("Nomor wallet tujuan tidak ditemukan")
-		}
 		return model.TransferResponse{}, err
 	}
-
-	// validation : Don't transfer it to yourself
-	if senderWalletID == receiverWalletID {
-		return model.TransferResponse{}, errors.New("Tidak bisa transfer ke wallet sendiri")
+	if err := checkRecipient(senderWalletID, receiver); err != nil {
+		return model.TransferResponse{}, err
 	}
+	receiverWalletID := receiver.WalletID
 
-	// update sender balance (decrease)
-	_, err = tx.ExecContext(ctx, "UPDATE wallets SET balance = balance - $1, updated_at = NOW() WHERE id = $2", req.Amount, senderWalletID)
+	senderAccount, err := walletAccountID(ctx, tx, senderWalletID, senderBalance)
+	if err != nil {
+		return model.TransferResponse{}, err
+	}
+	receiverAccount, err := walletAccountID(ctx, tx, receiverWalletID, receiverBalance)
 	if err != nil {
-		return model.TransferResponse{}, fmt.Errorf("Gagal potong saldo: %w", err)
+		return model.TransferResponse{}, err
+	}
+
+	// move the money: debit sender, credit receiver
+	reference := ulid.Make().String()
+	entry := &model.JournalEntry{
+		EntryType:   model.JournalTransfer,
+		Reference:   reference,
+		Description: req.Description,
+		Postings: []model.Posting{
+			{AccountID: senderAccount, Amount: req.Amount.Neg()},
+			{AccountID: receiverAccount, Amount: req.Amount},
+		},
+	}
+	if err := addFeePostings(ctx, tx, entry, senderAccount, fee); err != nil {
+		return model.TransferResponse{}, err
+	}
+	if err := postJournal(ctx, tx, entry); err != nil {
+		return model.TransferResponse{}, err
 	}
 
-	// update receiver balance (increase)
-	_, err = tx.ExecContext(ctx, "UPDATE wallets SET balance = balance + $1, updated_at = NOW() WHERE id = $2", req.Amount, receiverWalletID)
+	// record the transfer itself, both history rows point to it
+	var transferID int
+	createdAt := entry.CreatedAt
+	queryTransfer := `
+		INSERT INTO transfers (reference, sender_wallet_id, receiver_wallet_id, amount, fee, description, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	err = tx.QueryRowContext(ctx, queryTransfer, reference, senderWalletID, receiverWalletID, req.Amount, fee, req.Description, createdAt).Scan(&transferID)
 	if err != nil {
-		return model.TransferResponse{}, fmt.Errorf("Gagal tambah saldo: %w", err)
+		return model.TransferResponse{}, fmt.Errorf("Gagal catat transfer: %w", err)
 	}
 
-	// record history (double entry)
+	// record history for both wallets
 	// record for sender (money out)
-	var createdAt time.Time
-	queryHistoryOut := `
-		INSERT INTO transactions (wallet_id, transaction_type, amount, description, created_at) VALUES ($1, 'TRANSFER_OUT', $2, $3, NOW()) RETURNING created_at
+	queryHistory := `
+		INSERT INTO transactions (wallet_id, transaction_type, amount, balance_after, description, transfer_id, journal_entry_id, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
-	err = tx.QueryRowContext(ctx, queryHistoryOut, senderWalletID, req.Amount, "Transfer ke "+req.TargetWalletNumber).Scan(&createdAt)
+	_, err = tx.ExecContext(ctx, queryHistory, senderWalletID, model.TransactionTypeTransferOut, req.Amount, senderBalance.Sub(req.Amount), "Transfer ke "+req.TargetWalletNumber, transferID, entry.ID, createdAt)
 	if err != nil {
 		return model.TransferResponse{}, fmt.Errorf("Gagal catat history pengirim: %w", err)
 	}
 
 	// record for receiver (money in)
-	queryHistoryIn := `
-		INSERT INTO transactions (wallet_id, transaction_type, amount, description, created_at) VALUES ($1, 'TRANSFER_IN', $2, $3, NOW())
-	`
-
-	_, err = tx.ExecContext(ctx, queryHistoryIn, receiverWalletID, req.Amount, "Terima transfer")
+	_, err = tx.ExecContext(ctx, queryHistory, receiverWalletID, model.TransactionTypeTransferIn, req.Amount, receiverBalance.Add(req.Amount), "Terima transfer", transferID, entry.ID, createdAt)
 	if err != nil {
 		return model.TransferResponse{}, fmt.Errorf("Gagal catat history penerima: %w", err)
 	}
 
+	senderBalanceAfter := senderBalance.Sub(req.Amount)
+	if !fee.IsZero() {
+		senderBalanceAfter = senderBalanceAfter.Sub(fee)
+		if err := insertFeeRow(ctx, tx, senderWalletID, fee, senderBalanceAfter, "Biaya transfer ke "+req.TargetWalletNumber, &transferID, entry); err != nil {
+			return model.TransferResponse{}, err
+		}
+	}
+
 	// commit all
 	if err := tx.Commit(); err != nil {
 		return model.TransferResponse{}, err
 	}
 
 	return model.TransferResponse{
-		ID:             fmt.Sprintf("TRX-%d-%d", senderWalletID, time.Now().Unix()),
-		SenderBalance:  senderBalance - req.Amount,
+		ID:             reference,
+		SenderWallet:   senderWalletNumber,
+		SenderBalance:  senderBalanceAfter,
 		ReceiverWallet: req.TargetWalletNumber,
 		Amount:         req.Amount,
+		Fee:            fee,
 		CreatedAt:      createdAt,
 	}, nil
 }
 
-func (r *transactionRepositoryPostgres) GetTransactionHistory(ctx context.Context, userID int) ([]model.Transaction, error) {
-	query := `
-		SELECT t.id, t.wallet_id, t.transaction_type, t.amount, t.description, t.created_at
+// GetTransactionHistory returns up to filter.Limit rows, newest first, using keyset
+// pagination on (created_at, id) so pages stay stable while new rows are inserted.
+func (r *transactionRepositoryPostgres) GetTransactionHistory(ctx context.Context, userID int, filter model.TransactionFilter) ([]model.Transaction, error) {
+	conditions := []string{"w.user_id = $1"}
+	args := []interface{}{userID}
+	where := func(condition string, values ...interface{}) {
+		placeholders := make([]interface{}, len(values))
+		for i, v := range values {
+			args = append(args, v)
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	if filter.WalletID != 0 {
+		where("t.wallet_id = $%d", filter.WalletID)
+	}
+	if filter.AfterID != 0 {
+		where("(t.created_at, t.id) < ($%d, $%d)", filter.AfterCreatedAt, filter.AfterID)
+	}
+	if filter.TransactionType != "" {
+		where("t.transaction_type = $%d", filter.TransactionType)
+	}
+	if filter.From != nil {
+		where("t.created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		// "to" is a whole day, inclusive
+		where("t.created_at < $%d", filter.To.AddDate(0, 0, 1))
+	}
+	if filter.MinAmount != nil {
+		where("t.amount >= $%d", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		where("t.amount <= $%d", *filter.MaxAmount)
+	}
+	args = append(args, filter.Limit)
+
+	query := fmt.Sprintf(`
+		SELECT t.id, t.wallet_id, t.transaction_type, t.amount, t.description, COALESCE(tf.reference, ''), t.created_at
 		FROM transactions t
 		JOIN wallets w ON t.wallet_id = w.id
-		WHERE w.user_id = $1
-		ORDER BY t.created_at DESC
-		LIMIT 10
-	`
+		LEFT JOIN transfers tf ON t.transfer_id = tf.id
+		WHERE %s
+		ORDER BY t.created_at DESC, t.id DESC
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), len(args))
 
-	rows, err := r.DB.QueryContext(ctx, query, userID)
+	rows, err := r.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -183,7 +325,7 @@ func (r *transactionRepositoryPostgres) GetTransactionHistory(ctx context.Contex
 	var transactions []model.Transaction
 	for rows.Next() {
 		var t model.Transaction
-		if err := rows.Scan(&t.ID, &t.WalletID, &t.TransactionType, &t.Amount, &t.Description, &t.CreatedAt); err != nil {
+		if err := rows.Scan(&t.ID, &t.WalletID, &t.TransactionType, &t.Amount, &t.Description, &t.TransferRef, &t.CreatedAt); err != nil {
 			return nil, err
 		}
 		transactions = append(transactions, t)
@@ -195,3 +337,122 @@ func (r *transactionRepositoryPostgres) GetTransactionHistory(ctx context.Contex
 
 	return transactions, nil
 }
+
+// FindTransferByReference only returns transfers where the user is the sender or the receiver.
+func (r *transactionRepositoryPostgres) FindTransferByReference(ctx context.Context, userID int, reference string) (*model.Transfer, error) {
+	query := `
+		SELECT tf.reference, sw.wallet_number, rw.wallet_number, tf.amount, tf.fee, COALESCE(tf.description, ''), tf.created_at
+		FROM transfers tf
+		JOIN wallets sw ON tf.sender_wallet_id = sw.id
+		JOIN wallets rw ON tf.receiver_wallet_id = rw.id
+		WHERE tf.reference = $1 AND (sw.user_id = $2 OR rw.user_id = $2)
+	`
+
+	var t model.Transfer
+	err := r.DB.QueryRowContext(ctx, query, reference, userID).Scan(&t.Reference, &t.SenderWallet, &t.ReceiverWallet, &t.Amount, &t.Fee, &t.Description, &t.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTransferNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// FindTransactionByID returns a history row of one of the user's wallets together with the
+// other side of the transfer it belongs to (if any).
+func (r *transactionRepositoryPostgres) FindTransactionByID(ctx context.Context, userID int, id int) (*model.TransactionDetail, error) {
+	query := `
+		SELECT t.id, t.wallet_id, t.transaction_type, t.amount, t.description, COALESCE(tf.reference, ''), t.created_at,
+			t.balance_after, cw.wallet_number, cu.name
+		FROM transactions t
+		JOIN wallets w ON t.wallet_id = w.id
+		LEFT JOIN transfers tf ON t.transfer_id = tf.id
+		LEFT JOIN wallets cw ON cw.id = CASE WHEN tf.sender_wallet_id = t.wallet_id THEN tf.receiver_wallet_id ELSE tf.sender_wallet_id END
+		LEFT JOIN users cu ON cu.id = cw.user_id
+		WHERE t.id = $1 AND w.user_id = $2
+	`
+
+	var d model.TransactionDetail
+	var counterpartyWallet, counterpartyName sql.NullString
+	err := r.DB.QueryRowContext(ctx, query, id, userID).Scan(
+		&d.ID, &d.WalletID, &d.TransactionType, &d.Amount, &d.Description, &d.TransferRef, &d.CreatedAt,
+		&d.BalanceAfter, &counterpartyWallet, &counterpartyName,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, err
+	}
+
+	if counterpartyWallet.Valid {
+		d.Counterparty = &model.Counterparty{
+			WalletNumber: counterpartyWallet.String,
+			Name:         counterpartyName.String,
+		}
+	}
+	return &d, nil
+}
+
+// GetStatement returns the header of the user's pocket numbered walletNumber (the primary one when
+// empty) with its balance at `from` (OpeningBalance) and the
+// history rows in [from, to) oldest first. Both are read from one snapshot so the opening
+// balance and the rows always agree.
+func (r *transactionRepositoryPostgres) GetStatement(ctx context.Context, userID int, walletNumber string, from, to time.Time) (*model.Statement, []model.Transaction, error) {
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	// opening = current balance minus everything that happened since the period started
+	queryHeader := `
+		SELECT w.id, w.wallet_number, u.name, w.balance - COALESCE((
+			SELECT SUM(CASE WHEN t.transaction_type IN ('TRANSFER_OUT', 'FEE') THEN -t.amount ELSE t.amount END)
+			FROM transactions t
+			WHERE t.wallet_id = w.id AND t.created_at >= $3
+		), 0)
+		FROM wallets w
+		JOIN users u ON u.id = w.user_id
+		WHERE w.id = (SELECT id FROM wallets WHERE ` + ownWalletCondition + `)
+	`
+
+	var walletID int
+	st := model.Statement{PeriodStart: from, PeriodEnd: to}
+	err = tx.QueryRowContext(ctx, queryHeader, userID, walletNumber, from).Scan(&walletID, &st.WalletNumber, &st.OwnerName, &st.OpeningBalance)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, ErrWalletNotFound
+		}
+		return nil, nil, err
+	}
+
+	queryRows := `
+		SELECT t.id, t.wallet_id, t.transaction_type, t.amount, COALESCE(t.description, ''), COALESCE(tf.reference, ''), t.created_at
+		FROM transactions t
+		LEFT JOIN transfers tf ON t.transfer_id = tf.id
+		WHERE t.wallet_id = $1 AND t.created_at >= $2 AND t.created_at < $3
+		ORDER BY t.created_at, t.id
+	`
+
+	rows, err := tx.QueryContext(ctx, queryRows, walletID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var transactions []model.Transaction
+	for rows.Next() {
+		var t model.Transaction
+		if err := rows.Scan(&t.ID, &t.WalletID, &t.TransactionType, &t.Amount, &t.Description, &t.TransferRef, &t.CreatedAt); err != nil {
+			return nil, nil, err
+		}
+		transactions = append(transactions, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return &st, transactions, nil
+}
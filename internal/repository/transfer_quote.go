@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"ewallet-service/internal/model"
+)
+
+var ErrTransferQuoteInvalid = errors.New("Quote transfer tidak valid, kadaluarsa atau sudah dipakai")
+
+// queryRower is a *sql.DB or a *sql.Tx
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// ownWalletCondition picks the user's ($1) pocket numbered $2, or the primary pocket when $2 is empty.
+const ownWalletCondition = "user_id = $1 AND (wallet_number = $2 OR ($2 = '' AND is_primary))"
+
+// findRecipient is the lookup of the wallet a transfer credits, shared by quotes and transfers.
+// Inside a transfer the wallet row is locked, so its status cannot change before the credit.
+func findRecipient(ctx context.Context, q queryRower, walletNumber string, lock bool) (*model.Recipient, model.Money, error) {
+	query := `
+		SELECT w.id, w.user_id, w.wallet_number, u.name, w.status, w.balance
+		FROM wallets w
+		JOIN users u ON u.id = w.user_id
+		WHERE w.wallet_number = $1
+	`
+	if lock {
+		query += " FOR UPDATE OF w"
+	}
+
+	var rec model.Recipient
+	var balance model.Money
+	err := q.QueryRowContext(ctx, query, walletNumber).Scan(&rec.WalletID, &rec.UserID, &rec.WalletNumber, &rec.Name, &rec.Status, &balance)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.Money{}, ErrRecipientNotFound
+		}
+		return nil, model.Money{}, err
+	}
+	return &rec, balance, nil
+}
+
+// checkRecipient refuses transfers to the sending pocket itself and to wallets that cannot be credited.
+func checkRecipient(senderWalletID int, rec *model.Recipient) error {
+	if rec.WalletID == senderWalletID {
+		return ErrSelfTransfer
+	}
+	if !model.WalletCanCredit(rec.Status) {
+		return ErrRecipientWalletUnavailable
+	}
+	return nil
+}
+
+// FindRecipient validates the recipient of a transfer from senderID's pocket sourceWalletNumber
+// the same way Transfer does.
+func (r *transactionRepositoryPostgres) FindRecipient(ctx context.Context, senderID int, sourceWalletNumber, walletNumber string) (*model.Recipient, error) {
+	var senderWalletID int
+	err := r.DB.QueryRowContext(ctx, "SELECT id FROM wallets WHERE "+ownWalletCondition, senderID, sourceWalletNumber).Scan(&senderWalletID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrWalletNotFound
+		}
+		return nil, err
+	}
+
+	rec, _, err := findRecipient(ctx, r.DB, walletNumber, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRecipient(senderWalletID, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// FindWalletByNumber looks a wallet up the same way as a transfer would, without judging it.
+func (r *transactionRepositoryPostgres) FindWalletByNumber(ctx context.Context, walletNumber string) (*model.Recipient, error) {
+	rec, _, err := findRecipient(ctx, r.DB, walletNumber, false)
+	return rec, err
+}
+
+func (r *transactionRepositoryPostgres) CreateTransferQuote(ctx context.Context, quote *model.TransferQuote) error {
+	query := `
+		INSERT INTO transfer_quotes (id, user_id, source_wallet_number, target_wallet_number, amount, fee, description, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.DB.ExecContext(ctx, query, quote.ID, quote.UserID, quote.SourceWalletNumber, quote.TargetWalletNumber, quote.Amount, quote.Fee, quote.Description, quote.ExpiresAt)
+	return err
+}
+
+// FindTransferQuote returns a quote of the user that can still be confirmed.
+func (r *transactionRepositoryPostgres) FindTransferQuote(ctx context.Context, userID int, id string) (*model.TransferQuote, error) {
+	query := `
+		SELECT id, user_id, source_wallet_number, target_wallet_number, amount, fee, COALESCE(description, ''), expires_at
+		FROM transfer_quotes
+		WHERE id = $1 AND user_id = $2 AND used_at IS NULL AND expires_at > NOW()
+	`
+
+	var q model.TransferQuote
+	err := r.DB.QueryRowContext(ctx, query, id, userID).Scan(&q.ID, &q.UserID, &q.SourceWalletNumber, &q.TargetWalletNumber, &q.Amount, &q.Fee, &q.Description, &q.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTransferQuoteInvalid
+		}
+		return nil, err
+	}
+	q.Total = q.Amount.Add(q.Fee)
+	return &q, nil
+}
+
+// consumeTransferQuote marks the quote used inside the transfer's transaction: a failed transfer
+// rolls it back, and of two confirms of the same quote only one gets past the UPDATE.
+func consumeTransferQuote(ctx context.Context, tx *sql.Tx, userID int, id string) error {
+	query := `
+		UPDATE transfer_quotes SET used_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND used_at IS NULL AND expires_at > NOW()
+	`
+	res, err := tx.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrTransferQuoteInvalid
+	}
+	return nil
+}
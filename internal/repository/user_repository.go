@@ -3,9 +3,10 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"ewallet-service/internal/model"
 	"fmt"
-	"math/rand"
+	"time"
 )
 
 type UserRepository interface {
@@ -13,6 +14,21 @@ type UserRepository interface {
 	EmailExists(ctx context.Context, email string) (bool, error)
 	FindByEmail(ctx context.Context, email string) (*model.User, error)
 	FindWalletByUserID(ctx context.Context, userID int) (*model.Wallet, error)
+	FindByID(ctx context.Context, userID int) (*model.User, error)
+	GetPINState(ctx context.Context, userID int) (*model.PINState, error)
+	SetPIN(ctx context.Context, userID int, pinHash string) error
+	ClaimPINAttempt(ctx context.Context, userID int, maxAttempts int, lockFor time.Duration) (*model.PINState, error)
+	ResetPINFailures(ctx context.Context, userID int) error
+	GetTOTPState(ctx context.Context, userID int) (*model.TOTPState, error)
+	SetTOTPSecret(ctx context.Context, userID int, secret string) error
+	EnableTOTP(ctx context.Context, userID int, recoveryCodeHashes []string) error
+	DisableTOTP(ctx context.Context, userID int) error
+	MarkTOTPStepUsed(ctx context.Context, userID int, step int64) (bool, error)
+	ClaimOTPAttempt(ctx context.Context, userID int, maxAttempts int, lockFor time.Duration) (*time.Time, error)
+	ResetOTPFailures(ctx context.Context, userID int) error
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+	MarkEmailVerified(ctx context.Context, userID int) error
+	UpdatePassword(ctx context.Context, userID int, passwordHash string) error
 }
 
 type userRepositoryPostgres struct {
@@ -36,25 +52,19 @@ func (r *userRepositoryPostgres) RegisterUser(ctx context.Context, user *model.U
 	var userID int
 	err = tx.QueryRowContext(ctx, sqlUser, user.Name, user.Email, user.Password).Scan(&userID)
 	if err != nil {
+		// EmailExists was checked before, but another request may have registered the email since
+		if isUniqueViolation(err, "users_email_key") {
+			return model.Wallet{}, ErrEmailTaken
+		}
 		return model.Wallet{}, fmt.Errorf("Gagal insert user: %w", err)
 	}
 	user.ID = userID
 
-	// generate wallet number (10 digits random)
-	walletNumber := fmt.Sprintf("100%d", rand.Intn(9999999))
-
-	// insert wallet (automatic balance 0)
-	sqlWallet := "INSERT INTO wallets (user_id, wallet_number, balance) VALUES ($1, $2, 0) RETURNING id, balance, created_at"
-
-	var wallet model.Wallet
-	err = tx.QueryRowContext(ctx, sqlWallet, userID, walletNumber).Scan(&wallet.ID, &wallet.Balance, &wallet.CreatedAt)
+	wallet, err := insertWallet(ctx, tx, userID, model.PrimaryPocketName, true)
 	if err != nil {
-		return model.Wallet{}, fmt.Errorf("Gagal insert wallet: %w", err)
+		return model.Wallet{}, err
 	}
 
-	wallet.UserID = userID
-	wallet.WalletNumber = walletNumber
-
 	if err := tx.Commit(); err != nil {
 		return model.Wallet{}, err
 	}
@@ -63,6 +73,44 @@ func (r *userRepositoryPostgres) RegisterUser(ctx context.Context, user *model.U
 
 }
 
+// walletNumberAttempts bounds the retries on a taken wallet number; with 10^8 numbers
+// a second collision in a row means something else is wrong.
+const walletNumberAttempts = 5
+
+// insertWallet creates a pocket of the user (balance 0) under a new random number, together with
+// its ledger account. A number that is already taken is skipped by ON CONFLICT instead of failing,
+// which would abort the transaction.
+func insertWallet(ctx context.Context, tx *sql.Tx, userID int, name string, primary bool) (model.Wallet, error) {
+	sqlWallet := `
+		INSERT INTO wallets (user_id, name, is_primary, wallet_number, balance) VALUES ($1, $2, $3, $4, 0)
+		ON CONFLICT (wallet_number) DO NOTHING
+		RETURNING id, balance, status, created_at
+	`
+
+	for attempt := 0; attempt < walletNumberAttempts; attempt++ {
+		walletNumber, err := model.GenerateWalletNumber()
+		if err != nil {
+			return model.Wallet{}, err
+		}
+
+		wallet := model.Wallet{UserID: userID, Name: name, IsPrimary: primary, WalletNumber: walletNumber}
+		err = tx.QueryRowContext(ctx, sqlWallet, userID, name, primary, walletNumber).Scan(&wallet.ID, &wallet.Balance, &wallet.Status, &wallet.CreatedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return model.Wallet{}, fmt.Errorf("Gagal insert wallet: %w", err)
+		}
+
+		// every wallet has its own ledger account
+		if _, err := walletAccountID(ctx, tx, wallet.ID, wallet.Balance); err != nil {
+			return model.Wallet{}, err
+		}
+		return wallet, nil
+	}
+	return model.Wallet{}, fmt.Errorf("Gagal insert wallet: tidak ada nomor wallet yang bebas setelah %d percobaan", walletNumberAttempts)
+}
+
 func (r *userRepositoryPostgres) EmailExists(ctx context.Context, email string) (bool, error) {
 	var exists bool
 	query := "SELECT EXISTS(SELECT 1 FROM users WHERE email=$1)"
@@ -71,11 +119,11 @@ func (r *userRepositoryPostgres) EmailExists(ctx context.Context, email string)
 }
 
 func (r *userRepositoryPostgres) FindByEmail(ctx context.Context, email string) (*model.User, error) {
-	query := "SELECT id, name, email, password FROM users WHERE email=$1"
+	query := "SELECT id, name, email, password, totp_enabled, email_verified_at IS NOT NULL, role, frozen_at IS NOT NULL FROM users WHERE email=$1"
 
 	var user model.User
 
-	err := r.DB.QueryRowContext(ctx, query, email).Scan(&user.ID, &user.Name, &user.Email, &user.Password)
+	err := r.DB.QueryRowContext(ctx, query, email).Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.TOTPEnabled, &user.EmailVerified, &user.Role, &user.Frozen)
 
 	if err != nil {
 		return nil, err
@@ -84,13 +132,201 @@ func (r *userRepositoryPostgres) FindByEmail(ctx context.Context, email string)
 	return &user, nil
 }
 
+// FindWalletByUserID returns the user's primary pocket.
 func (r *userRepositoryPostgres) FindWalletByUserID(ctx context.Context, userID int) (*model.Wallet, error) {
-	query := "SELECT id, user_id, balance, wallet_number, created_at FROM wallets WHERE user_id = $1"
+	query := "SELECT " + walletColumns + " FROM wallets WHERE user_id = $1 AND is_primary"
+
+	w, err := scanWallet(r.DB.QueryRowContext(ctx, query, userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrWalletNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (r *userRepositoryPostgres) FindByID(ctx context.Context, userID int) (*model.User, error) {
+	query := `
+		SELECT id, name, email, password, totp_enabled, email_verified_at IS NOT NULL, role, frozen_at IS NOT NULL, created_at, updated_at
+		FROM users WHERE id = $1
+	`
+
+	var user model.User
+	err := r.DB.QueryRowContext(ctx, query, userID).Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.TOTPEnabled, &user.EmailVerified, &user.Role, &user.Frozen, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepositoryPostgres) GetPINState(ctx context.Context, userID int) (*model.PINState, error) {
+	query := "SELECT COALESCE(pin_hash, ''), pin_failed_attempts, pin_locked_until FROM users WHERE id = $1"
+
+	var s model.PINState
+	err := r.DB.QueryRowContext(ctx, query, userID).Scan(&s.Hash, &s.FailedAttempts, &s.LockedUntil)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *userRepositoryPostgres) SetPIN(ctx context.Context, userID int, pinHash string) error {
+	query := "UPDATE users SET pin_hash = $1, pin_failed_attempts = 0, pin_locked_until = NULL, updated_at = NOW() WHERE id = $2"
+	_, err := r.DB.ExecContext(ctx, query, pinHash, userID)
+	return err
+}
+
+// ClaimPINAttempt counts an attempt before the PIN is compared, in one statement, so parallel
+// guesses cannot all slip past the lock: the attempt that reaches maxAttempts sets the lock for
+// lockFor (and starts the counter again), and every attempt after it gets ErrPINLocked.
+// The returned state holds the hash to compare against; the caller resets the counter
+// when the PIN was right.
+func (r *userRepositoryPostgres) ClaimPINAttempt(ctx context.Context, userID int, maxAttempts int, lockFor time.Duration) (*model.PINState, error) {
+	query := `
+		UPDATE users SET
+			pin_locked_until = CASE WHEN pin_failed_attempts + 1 >= $2 THEN NOW() + make_interval(secs => $3) ELSE NULL END,
+			pin_failed_attempts = CASE WHEN pin_failed_attempts + 1 >= $2 THEN 0 ELSE pin_failed_attempts + 1 END
+		WHERE id = $1 AND pin_hash IS NOT NULL AND (pin_locked_until IS NULL OR pin_locked_until <= NOW())
+		RETURNING pin_hash, pin_failed_attempts, pin_locked_until
+	`
+
+	var s model.PINState
+	err := r.DB.QueryRowContext(ctx, query, userID, maxAttempts, lockFor.Seconds()).Scan(&s.Hash, &s.FailedAttempts, &s.LockedUntil)
+	if err == sql.ErrNoRows {
+		return nil, ErrPINLocked
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *userRepositoryPostgres) ResetPINFailures(ctx context.Context, userID int) error {
+	_, err := r.DB.ExecContext(ctx, "UPDATE users SET pin_failed_attempts = 0, pin_locked_until = NULL WHERE id = $1", userID)
+	return err
+}
+
+func (r *userRepositoryPostgres) GetTOTPState(ctx context.Context, userID int) (*model.TOTPState, error) {
+	query := "SELECT COALESCE(totp_secret, ''), totp_enabled, COALESCE(totp_last_step, 0) FROM users WHERE id = $1"
+
+	var s model.TOTPState
+	err := r.DB.QueryRowContext(ctx, query, userID).Scan(&s.Secret, &s.Enabled, &s.LastStep)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// SetTOTPSecret stores a pending secret. It never replaces the secret of an enabled 2FA.
+func (r *userRepositoryPostgres) SetTOTPSecret(ctx context.Context, userID int, secret string) error {
+	query := "UPDATE users SET totp_secret = $1, totp_last_step = NULL, updated_at = NOW() WHERE id = $2 AND NOT totp_enabled"
+	_, err := r.DB.ExecContext(ctx, query, secret, userID)
+	return err
+}
+
+// EnableTOTP turns 2FA on and replaces the recovery codes in one transaction.
+func (r *userRepositoryPostgres) EnableTOTP(ctx context.Context, userID int, recoveryCodeHashes []string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET totp_enabled = TRUE, updated_at = NOW() WHERE id = $1", userID); err != nil {
+		return fmt.Errorf("Gagal mengaktifkan 2FA: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+
+	queryCode := "INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)"
+	for _, hash := range recoveryCodeHashes {
+		if _, err := tx.ExecContext(ctx, queryCode, userID, hash); err != nil {
+			return fmt.Errorf("Gagal menyimpan recovery code: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *userRepositoryPostgres) DisableTOTP(ctx context.Context, userID int) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE users SET totp_secret = NULL, totp_enabled = FALSE, totp_last_step = NULL, updated_at = NOW() WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MarkTOTPStepUsed records the step of an accepted code. It returns false when that step
+// (or a later one) was already used, so the same code cannot be used twice.
+func (r *userRepositoryPostgres) MarkTOTPStepUsed(ctx context.Context, userID int, step int64) (bool, error) {
+	query := "UPDATE users SET totp_last_step = $1 WHERE id = $2 AND (totp_last_step IS NULL OR totp_last_step < $1)"
+	res, err := r.DB.ExecContext(ctx, query, step, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// ClaimOTPAttempt counts a 2FA code attempt like ClaimPINAttempt counts PIN attempts. It returns
+// the lock this attempt set when it was the last one allowed, nil otherwise, and ErrOTPLocked while
+// a lock is in force.
+func (r *userRepositoryPostgres) ClaimOTPAttempt(ctx context.Context, userID int, maxAttempts int, lockFor time.Duration) (*time.Time, error) {
+	query := `
+		UPDATE users SET
+			otp_locked_until = CASE WHEN otp_failed_attempts + 1 >= $2 THEN NOW() + make_interval(secs => $3) ELSE NULL END,
+			otp_failed_attempts = CASE WHEN otp_failed_attempts + 1 >= $2 THEN 0 ELSE otp_failed_attempts + 1 END
+		WHERE id = $1 AND (otp_locked_until IS NULL OR otp_locked_until <= NOW())
+		RETURNING otp_locked_until
+	`
 
-	var w model.Wallet
-	err := r.DB.QueryRowContext(ctx, query, userID).Scan(&w.ID, &w.UserID, &w.Balance, &w.WalletNumber, &w.CreatedAt)
+	var lockedUntil *time.Time
+	err := r.DB.QueryRowContext(ctx, query, userID, maxAttempts, lockFor.Seconds()).Scan(&lockedUntil)
+	if err == sql.ErrNoRows {
+		return nil, ErrOTPLocked
+	}
 	if err != nil {
 		return nil, err
 	}
-	return &w, nil
+	return lockedUntil, nil
+}
+
+func (r *userRepositoryPostgres) ResetOTPFailures(ctx context.Context, userID int) error {
+	_, err := r.DB.ExecContext(ctx, "UPDATE users SET otp_failed_attempts = 0, otp_locked_until = NULL WHERE id = $1", userID)
+	return err
+}
+
+// UseRecoveryCode burns a recovery code. It returns false for unknown or already used codes.
+func (r *userRepositoryPostgres) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	query := "UPDATE recovery_codes SET used_at = NOW() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL"
+	res, err := r.DB.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (r *userRepositoryPostgres) MarkEmailVerified(ctx context.Context, userID int) error {
+	query := "UPDATE users SET email_verified_at = COALESCE(email_verified_at, NOW()), updated_at = NOW() WHERE id = $1"
+	_, err := r.DB.ExecContext(ctx, query, userID)
+	return err
+}
+
+func (r *userRepositoryPostgres) UpdatePassword(ctx context.Context, userID int, passwordHash string) error {
+	query := "UPDATE users SET password = $1, updated_at = NOW() WHERE id = $2"
+	_, err := r.DB.ExecContext(ctx, query, passwordHash, userID)
+	return err
 }
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"errors"
+	"ewallet-service/internal/model"
+)
+
+// refusals because of a wallet's status. The recipient's exact state is not revealed to the sender.
+var (
+	ErrWalletFrozen               = errors.New("Wallet sedang dibekukan, hubungi customer service")
+	ErrWalletClosed               = errors.New("Wallet sudah ditutup")
+	ErrWalletDebitBlocked         = errors.New("Wallet diblokir untuk transaksi keluar")
+	ErrWalletCreditBlocked        = errors.New("Wallet diblokir untuk menerima dana")
+	ErrRecipientWalletUnavailable = errors.New("Wallet tujuan tidak dapat menerima dana")
+	// closing is final, so a wallet is only closed once its money has been moved out
+	ErrWalletNotEmpty = errors.New("Wallet masih memiliki saldo, kosongkan sebelum ditutup")
+)
+
+// checkDebit returns why money may not leave the caller's own wallet, or nil.
+func checkDebit(status string) error {
+	if model.WalletCanDebit(status) {
+		return nil
+	}
+	return ownWalletError(status)
+}
+
+// checkCredit returns why money may not enter the caller's own wallet, or nil.
+func checkCredit(status string) error {
+	if model.WalletCanCredit(status) {
+		return nil
+	}
+	return ownWalletError(status)
+}
+
+func ownWalletError(status string) error {
+	switch status {
+	case model.WalletFrozen:
+		return ErrWalletFrozen
+	case model.WalletClosed:
+		return ErrWalletClosed
+	case model.WalletDebitBlocked:
+		return ErrWalletDebitBlocked
+	default:
+		return ErrWalletCreditBlocked
+	}
+}
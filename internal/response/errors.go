@@ -0,0 +1,171 @@
+package response
+
+import (
+	"errors"
+	"ewallet-service/internal/i18n"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"ewallet-service/internal/usecase"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// request-level errors raised by the handlers and middleware themselves
+var (
+	ErrUnauthorized          = errors.New("Unauthorized")
+	ErrMissingToken          = errors.New("Token tidak ditemukan atau format salah")
+	ErrForbidden             = errors.New("Akses ditolak")
+	ErrIdempotencyKeyTooLong = errors.New("Idempotency-Key terlalu panjang (maks 255 karakter)")
+	ErrUnreadableBody        = errors.New("Gagal membaca request body")
+)
+
+// errorMapping ties a domain error to its HTTP status and to the code clients can switch on.
+// Codes are part of the API: never rename one, add a new one instead.
+type errorMapping struct {
+	err    error
+	status int
+	code   string
+}
+
+var errorMappings = []errorMapping{
+	// authentication & session
+	{ErrUnauthorized, http.StatusUnauthorized, "UNAUTHORIZED"},
+	{ErrMissingToken, http.StatusUnauthorized, "TOKEN_MISSING"},
+	{usecase.ErrTokenInvalid, http.StatusUnauthorized, "TOKEN_INVALID"},
+	{usecase.ErrTokenRevoked, http.StatusUnauthorized, "TOKEN_REVOKED"},
+	{repository.ErrRefreshTokenInvalid, http.StatusUnauthorized, "REFRESH_TOKEN_INVALID"},
+	{repository.ErrRefreshTokenReused, http.StatusUnauthorized, "REFRESH_TOKEN_REUSED"},
+	{usecase.ErrInvalidCredentials, http.StatusUnauthorized, "INVALID_CREDENTIALS"},
+	{usecase.ErrLoginLocked, http.StatusTooManyRequests, "LOGIN_LOCKED"},
+	{usecase.ErrRateLimited, http.StatusTooManyRequests, "RATE_LIMITED"},
+	{usecase.ErrAccountFrozen, http.StatusForbidden, "ACCOUNT_FROZEN"},
+	{ErrForbidden, http.StatusForbidden, "FORBIDDEN"},
+
+	// account
+	{repository.ErrEmailTaken, http.StatusConflict, "EMAIL_TAKEN"},
+	{usecase.ErrEmailAlreadyVerified, http.StatusConflict, "EMAIL_ALREADY_VERIFIED"},
+	{repository.ErrActionTokenInvalid, http.StatusBadRequest, "ACTION_TOKEN_INVALID"},
+	{usecase.ErrWrongPassword, http.StatusForbidden, "WRONG_PASSWORD"},
+
+	// PIN & 2FA
+	{usecase.ErrPINNotSet, http.StatusForbidden, "PIN_NOT_SET"},
+	{usecase.ErrPINAlreadySet, http.StatusConflict, "PIN_ALREADY_SET"},
+	{usecase.ErrPINInvalid, http.StatusForbidden, "PIN_INVALID"},
+	{usecase.ErrPINLocked, http.StatusLocked, "PIN_LOCKED"},
+	{usecase.ErrTwoFactorAlreadyEnabled, http.StatusConflict, "TWO_FACTOR_ALREADY_ENABLED"},
+	{usecase.ErrTwoFactorNotEnrolled, http.StatusBadRequest, "TWO_FACTOR_NOT_ENROLLED"},
+	{usecase.ErrTwoFactorNotEnabled, http.StatusForbidden, "TWO_FACTOR_NOT_ENABLED"},
+	{usecase.ErrOTPInvalid, http.StatusForbidden, "OTP_INVALID"},
+	{usecase.ErrOTPLocked, http.StatusLocked, "OTP_LOCKED"},
+	{usecase.ErrOTPRequired, http.StatusForbidden, "OTP_REQUIRED"},
+	{usecase.ErrRecoveryCodeInvalid, http.StatusUnauthorized, "RECOVERY_CODE_INVALID"},
+
+	// money movement
+	{repository.ErrInsufficientFunds, http.StatusUnprocessableEntity, "INSUFFICIENT_FUNDS"},
+	{repository.ErrSelfTransfer, http.StatusUnprocessableEntity, "SELF_TRANSFER"},
+	{repository.ErrRecipientNotFound, http.StatusNotFound, "RECIPIENT_NOT_FOUND"},
+	{repository.ErrWalletFrozen, http.StatusForbidden, "WALLET_FROZEN"},
+	{repository.ErrWalletClosed, http.StatusForbidden, "WALLET_CLOSED"},
+	{repository.ErrWalletDebitBlocked, http.StatusForbidden, "WALLET_DEBIT_BLOCKED"},
+	{repository.ErrWalletCreditBlocked, http.StatusForbidden, "WALLET_CREDIT_BLOCKED"},
+	{repository.ErrRecipientWalletUnavailable, http.StatusUnprocessableEntity, "RECIPIENT_WALLET_UNAVAILABLE"},
+	{repository.ErrTransferQuoteInvalid, http.StatusUnprocessableEntity, "TRANSFER_QUOTE_INVALID"},
+	{usecase.ErrTransferQuoteFeeChanged, http.StatusConflict, "TRANSFER_QUOTE_FEE_CHANGED"},
+	{usecase.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED"},
+	{usecase.ErrIdempotencyInProgress, http.StatusConflict, "IDEMPOTENCY_IN_PROGRESS"},
+	{ErrIdempotencyKeyTooLong, http.StatusBadRequest, "IDEMPOTENCY_KEY_TOO_LONG"},
+	{ErrUnreadableBody, http.StatusBadRequest, "INVALID_INPUT"},
+
+	// limits
+	{usecase.ErrPerTransactionLimit, http.StatusUnprocessableEntity, "PER_TRANSACTION_LIMIT_EXCEEDED"},
+	{usecase.ErrDailyLimit, http.StatusUnprocessableEntity, "DAILY_LIMIT_EXCEEDED"},
+	{usecase.ErrMonthlyLimit, http.StatusUnprocessableEntity, "MONTHLY_LIMIT_EXCEEDED"},
+	{usecase.ErrRecipientLimit, http.StatusUnprocessableEntity, "RECIPIENT_LIMIT_EXCEEDED"},
+	{usecase.ErrFeeExceedsAmount, http.StatusUnprocessableEntity, "FEE_EXCEEDS_AMOUNT"},
+	// a server-side conflict: 503 releases the Idempotency-Key, so the same request can be retried
+	{repository.ErrLimitUsageChanged, http.StatusServiceUnavailable, "LIMIT_USAGE_CHANGED"},
+
+	// pockets
+	{usecase.ErrPocketNotFound, http.StatusNotFound, "POCKET_NOT_FOUND"},
+	{usecase.ErrInvalidPocketName, http.StatusBadRequest, "INVALID_POCKET_NAME"},
+	{repository.ErrPocketNameTaken, http.StatusConflict, "POCKET_NAME_TAKEN"},
+	{repository.ErrPocketLimit, http.StatusUnprocessableEntity, "POCKET_LIMIT_REACHED"},
+
+	// lookups & queries
+	{repository.ErrUserNotFound, http.StatusNotFound, "USER_NOT_FOUND"},
+	{repository.ErrWalletNotFound, http.StatusNotFound, "WALLET_NOT_FOUND"},
+	{repository.ErrTransferNotFound, http.StatusNotFound, "TRANSFER_NOT_FOUND"},
+	{repository.ErrTransactionNotFound, http.StatusNotFound, "TRANSACTION_NOT_FOUND"},
+	{usecase.ErrInvalidCursor, http.StatusBadRequest, "INVALID_CURSOR"},
+	{usecase.ErrInvalidFilter, http.StatusBadRequest, "INVALID_FILTER"},
+	{usecase.ErrInvalidPeriod, http.StatusBadRequest, "INVALID_PERIOD"},
+
+	// back office
+	{usecase.ErrUserSearchEmpty, http.StatusBadRequest, "USER_SEARCH_EMPTY"},
+	{usecase.ErrSelfAdministration, http.StatusForbidden, "SELF_ADMINISTRATION"},
+	{usecase.ErrStaffAccount, http.StatusForbidden, "STAFF_ACCOUNT"},
+	{usecase.ErrWalletReactivation, http.StatusForbidden, "WALLET_REACTIVATION_ADMIN_ONLY"},
+	{usecase.ErrWalletCloseAdminOnly, http.StatusForbidden, "WALLET_CLOSE_ADMIN_ONLY"},
+	{repository.ErrWalletNotEmpty, http.StatusUnprocessableEntity, "WALLET_NOT_EMPTY"},
+	{usecase.ErrWalletLoosenAdminOnly, http.StatusForbidden, "WALLET_LOOSEN_ADMIN_ONLY"},
+	{usecase.ErrAccountAlreadyFrozen, http.StatusConflict, "ACCOUNT_ALREADY_FROZEN"},
+	{usecase.ErrAccountNotFrozen, http.StatusConflict, "ACCOUNT_NOT_FROZEN"},
+}
+
+// Error writes the response for err and aborts the chain. Errors without a mapping
+// are logged and answered with a generic 500, so database details never reach the client.
+func Error(c *gin.Context, err error) {
+	for _, m := range errorMappings {
+		if !errors.Is(err, m.err) {
+			continue
+		}
+
+		var args []interface{}
+		var locked *usecase.LoginLockedError
+		if errors.As(err, &locked) {
+			args = append(args, retryAfter(c, locked.RetryAfter))
+		}
+		var limited *usecase.RateLimitedError
+		if errors.As(err, &limited) {
+			args = append(args, retryAfter(c, limited.RetryAfter))
+		}
+		var exceeded *usecase.LimitExceededError
+		if errors.As(err, &exceeded) {
+			args = append(args, exceeded.Remaining)
+		}
+		if errors.Is(err, repository.ErrPocketLimit) {
+			args = append(args, model.MaxPockets)
+		}
+
+		// a code without catalog entry keeps the error's own text rather than showing the bare code
+		message := err.Error()
+		if i18n.Has(i18n.Default, m.code) {
+			message = Translate(c, m.code, args...)
+		}
+
+		c.AbortWithStatusJSON(m.status, WebResponse{
+			Status:  "fail",
+			Code:    m.code,
+			Message: message,
+		})
+		return
+	}
+
+	log.Printf("%s %s: %v", c.Request.Method, c.FullPath(), err)
+	c.AbortWithStatusJSON(http.StatusInternalServerError, WebResponse{
+		Status:  "error",
+		Code:    "INTERNAL_ERROR",
+		Message: Translate(c, "INTERNAL_ERROR"),
+	})
+}
+
+// retryAfter sets the Retry-After header (whole seconds, rounded up) and returns the wait for the message.
+func retryAfter(c *gin.Context, wait time.Duration) time.Duration {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	return wait.Round(time.Second)
+}
@@ -0,0 +1,15 @@
+package response
+
+import (
+	"ewallet-service/internal/i18n"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorMappingsAreTranslated(t *testing.T) {
+	for _, m := range errorMappings {
+		assert.True(t, i18n.Has(i18n.ID, m.code), m.code)
+		assert.True(t, i18n.Has(i18n.EN, m.code), m.code)
+	}
+}
@@ -0,0 +1,111 @@
+package response_test
+
+import (
+	"encoding/json"
+	"errors"
+	"ewallet-service/internal/i18n"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"ewallet-service/internal/response"
+	"ewallet-service/internal/usecase"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func respond(err error, acceptLanguage ...string) (*httptest.ResponseRecorder, response.WebResponse) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/transfer", nil)
+	if len(acceptLanguage) > 0 {
+		c.Request.Header.Set("Accept-Language", acceptLanguage[0])
+	}
+
+	response.Error(c, err)
+
+	var res response.WebResponse
+	json.Unmarshal(w.Body.Bytes(), &res)
+	return w, res
+}
+
+func TestError_MapsDomainErrors(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{repository.ErrInsufficientFunds, http.StatusUnprocessableEntity, "INSUFFICIENT_FUNDS"},
+		{repository.ErrRecipientNotFound, http.StatusNotFound, "RECIPIENT_NOT_FOUND"},
+		{repository.ErrSelfTransfer, http.StatusUnprocessableEntity, "SELF_TRANSFER"},
+		{repository.ErrEmailTaken, http.StatusConflict, "EMAIL_TAKEN"},
+		{usecase.ErrPINLocked, http.StatusLocked, "PIN_LOCKED"},
+		// wrapped errors are still recognised
+		{fmt.Errorf("transfer: %w", repository.ErrWalletFrozen), http.StatusForbidden, "WALLET_FROZEN"},
+	}
+
+	for _, tc := range cases {
+		w, res := respond(tc.err)
+
+		assert.Equal(t, tc.status, w.Code, tc.code)
+		assert.Equal(t, "fail", res.Status)
+		assert.Equal(t, tc.code, res.Code)
+		assert.Equal(t, i18n.Message(i18n.ID, tc.code), res.Message)
+	}
+}
+
+func TestError_English(t *testing.T) {
+	w, res := respond(repository.ErrInsufficientFunds, "en-US,en;q=0.9")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "INSUFFICIENT_FUNDS", res.Code)
+	assert.Equal(t, "Insufficient balance", res.Message)
+}
+
+func TestError_LoginLockedSetsRetryAfter(t *testing.T) {
+	w, res := respond(&usecase.LoginLockedError{RetryAfter: 1500 * time.Millisecond})
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "LOGIN_LOCKED", res.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	assert.Contains(t, res.Message, "2s")
+}
+
+func TestError_RateLimitedSetsRetryAfter(t *testing.T) {
+	w, res := respond(&usecase.RateLimitedError{RetryAfter: 42 * time.Second}, "en")
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "RATE_LIMITED", res.Code)
+	assert.Equal(t, "42", w.Header().Get("Retry-After"))
+	assert.Equal(t, "Too many requests, try again in 42s", res.Message)
+}
+
+func TestError_LimitExceededShowsRemaining(t *testing.T) {
+	w, res := respond(&usecase.LimitExceededError{Err: usecase.ErrDailyLimit, Remaining: model.NewMoney(150000)}, "en")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "DAILY_LIMIT_EXCEEDED", res.Code)
+	assert.Equal(t, "Daily limit exceeded, 150000.00 left for today", res.Message)
+}
+
+func TestError_PocketLimitNamesMaximum(t *testing.T) {
+	w, res := respond(repository.ErrPocketLimit, "en")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "POCKET_LIMIT_REACHED", res.Code)
+	assert.Equal(t, fmt.Sprintf("Maximum number of pockets reached (%d)", model.MaxPockets), res.Message)
+}
+
+func TestError_UnknownErrorIsMasked(t *testing.T) {
+	w, res := respond(errors.New(`pq: relation "wallets" does not exist`))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "error", res.Status)
+	assert.Equal(t, "INTERNAL_ERROR", res.Code)
+	assert.NotContains(t, res.Message, "wallets")
+}
@@ -0,0 +1,24 @@
+package response
+
+import (
+	"ewallet-service/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LangKey is where middleware.Language stores the negotiated language.
+const LangKey = "lang"
+
+// Lang is the language of the response; without middleware.Language in front
+// (e.g. in tests) it is negotiated from the header directly.
+func Lang(c *gin.Context) string {
+	if lang := c.GetString(LangKey); lang != "" {
+		return lang
+	}
+	return i18n.Negotiate(c.GetHeader("Accept-Language"))
+}
+
+// Translate returns the catalog message for key in the language of the request.
+func Translate(c *gin.Context, key string, args ...interface{}) string {
+	return i18n.Message(Lang(c), key, args...)
+}
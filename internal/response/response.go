@@ -0,0 +1,20 @@
+// Package response renders the JSON envelope and the mapped errors shared by the handlers
+// and the middleware in front of them.
+package response
+
+type WebResponse struct {
+	Status  string       `json:"status"`
+	Code    string       `json:"code,omitempty"` // stabil untuk dibaca client, Message bisa berubah
+	Message string       `json:"message"`
+	Data    interface{}  `json:"data,omitempty"` // omitempty: kalau null, gak usah tampil
+	Error   string       `json:"error,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"` // per field, supaya frontend bisa tandai input yang salah
+}
+
+// FieldError describes one field that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`           // nama JSON/query, nested pakai titik: items[0].amount
+	Rule    string `json:"rule"`            // required, min, max, email, oneof, ...
+	Param   string `json:"param,omitempty"` // batas dari rule, mis. "6" untuk min=6
+	Message string `json:"message"`
+}
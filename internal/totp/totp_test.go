@@ -0,0 +1,78 @@
+package totp_test
+
+import (
+	"ewallet-service/internal/totp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// "12345678901234567890" in base32, the SHA1 secret of the RFC 6238 test vectors
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	// the RFC lists 8 digit codes, these are their last 6 digits
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range vectors {
+		code, err := totp.Code(rfcSecret, totp.Step(time.Unix(unix, 0)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code, "time %d", unix)
+	}
+}
+
+func TestValidate_AcceptsOneStepOfDrift(t *testing.T) {
+	// arrange
+	now := time.Unix(1234567890, 0)
+	previous, _ := totp.Code(rfcSecret, totp.Step(now)-1)
+	tooOld, _ := totp.Code(rfcSecret, totp.Step(now)-2)
+
+	// act
+	step, ok := totp.Validate(rfcSecret, previous, now)
+	_, okOld := totp.Validate(rfcSecret, tooOld, now)
+
+	// assert
+	assert.True(t, ok)
+	assert.Equal(t, totp.Step(now)-1, step)
+	assert.False(t, okOld)
+}
+
+func TestValidate_RejectsMalformedCode(t *testing.T) {
+	_, ok := totp.Validate(rfcSecret, "12345", time.Now())
+	assert.False(t, ok)
+}
+
+func TestNewSecret_RoundTrip(t *testing.T) {
+	// arrange
+	secret, err := totp.NewSecret()
+	assert.NoError(t, err)
+
+	now := time.Now()
+	code, err := totp.Code(secret, totp.Step(now))
+	assert.NoError(t, err)
+
+	// act
+	_, ok := totp.Validate(secret, code, now)
+
+	// assert
+	assert.True(t, ok)
+	assert.Len(t, secret, 32)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := totp.ProvisioningURI("E-Wallet", "budi@example.com", rfcSecret)
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/E-Wallet:budi@example.com?"))
+	assert.Contains(t, uri, "secret="+rfcSecret)
+	assert.Contains(t, uri, "issuer=E-Wallet")
+}
@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"ewallet-service/internal/jwtkeys"
+	"ewallet-service/internal/mailer"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/oklog/ulid/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	verifyEmailTokenTTL   = 24 * time.Hour
+	resetPasswordTokenTTL = 30 * time.Minute
+
+	// reset mails per address, whoever asks for them
+	resetMailsPerHour = 3
+	// verification mails per user and per address
+	verifyMailsPerHour = 3
+)
+
+var ErrEmailAlreadyVerified = errors.New("Email sudah terverifikasi")
+
+type actionClaims struct {
+	UserID  int    `json:"user_id"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// AccountUsecase handles the flows that go through the user's mailbox: email verification
+// and password reset. Their tokens are signed JWTs whose jti is stored, so each link
+// works once and only until it expires.
+type AccountUsecase struct {
+	UserRepo    repository.UserRepository
+	TokenRepo   repository.ActionTokenRepository
+	SessionRepo repository.SessionRepository
+	Mailer      mailer.Mailer
+	Keys        *jwtkeys.KeySet
+	// Attempts, when set, lets a password reset lift a login lockout
+	Attempts *LoginAttemptUsecase
+	// RateLimit, when set, caps the reset and verification mails sent to one address
+	RateLimit *RateLimitUsecase
+
+	// reset mails still being sent in the background
+	pending sync.WaitGroup
+}
+
+func NewAccountUsecase(userRepo repository.UserRepository, tokenRepo repository.ActionTokenRepository, sessionRepo repository.SessionRepository, m mailer.Mailer, keys *jwtkeys.KeySet) *AccountUsecase {
+	return &AccountUsecase{UserRepo: userRepo, TokenRepo: tokenRepo, SessionRepo: sessionRepo, Mailer: m, Keys: keys}
+}
+
+func (u *AccountUsecase) SendVerification(ctx context.Context, user *model.User) error {
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	token, err := u.issueToken(ctx, user.ID, model.TokenPurposeVerifyEmail, verifyEmailTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	return u.Mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verifikasi email akun E-Wallet",
+		Body: fmt.Sprintf("Halo %s,\n\nKlik link berikut untuk memverifikasi email kamu:\n%s\n\nLink berlaku 24 jam.\n",
+			user.Name, actionLink("VERIFY_EMAIL_PATH", "/verify-email", token)),
+	})
+}
+
+// ResendVerification mails a new verification link, at most verifyMailsPerHour times per user
+// and per address.
+func (u *AccountUsecase) ResendVerification(ctx context.Context, userID int) error {
+	user, err := u.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	if u.RateLimit != nil {
+		if err := u.RateLimit.Allow(ctx, fmt.Sprintf("email_resend:user:%d", userID), verifyMailsPerHour, time.Hour); err != nil {
+			return err
+		}
+		key := "email_resend:email:" + strings.ToLower(strings.TrimSpace(user.Email))
+		if err := u.RateLimit.Allow(ctx, key, verifyMailsPerHour, time.Hour); err != nil {
+			return err
+		}
+	}
+	return u.SendVerification(ctx, user)
+}
+
+func (u *AccountUsecase) VerifyEmail(ctx context.Context, req model.VerifyEmailRequest) error {
+	userID, err := u.consumeToken(ctx, req.Token, model.TokenPurposeVerifyEmail)
+	if err != nil {
+		return err
+	}
+	return u.UserRepo.MarkEmailVerified(ctx, userID)
+}
+
+// ForgotPassword mails a reset link. Unknown emails get the same (silent) success, so the
+// endpoint cannot be used to find out who has an account. The per-address limit is counted
+// before the lookup for the same reason, and the link is issued and mailed in the background:
+// both answers come right after the lookup, so their timing does not tell them apart either.
+func (u *AccountUsecase) ForgotPassword(ctx context.Context, req model.ForgotPasswordRequest) error {
+	if u.RateLimit != nil {
+		key := "password_forgot:email:" + strings.ToLower(strings.TrimSpace(req.Email))
+		if err := u.RateLimit.Allow(ctx, key, resetMailsPerHour, time.Hour); err != nil {
+			return err
+		}
+	}
+
+	user, err := u.UserRepo.FindByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	// the request may be gone before the mail is out
+	mailCtx := context.WithoutCancel(ctx)
+	u.pending.Add(1)
+	go func() {
+		defer u.pending.Done()
+		if err := u.sendResetLink(mailCtx, user); err != nil {
+			log.Printf("Gagal mengirim email reset password ke %s: %v", user.Email, err)
+		}
+	}()
+	return nil
+}
+
+// Wait blocks until the reset mails sent in the background are out.
+func (u *AccountUsecase) Wait() {
+	u.pending.Wait()
+}
+
+func (u *AccountUsecase) sendResetLink(ctx context.Context, user *model.User) error {
+	token, err := u.issueToken(ctx, user.ID, model.TokenPurposeResetPassword, resetPasswordTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	return u.Mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset password akun E-Wallet",
+		Body: fmt.Sprintf("Halo %s,\n\nKlik link berikut untuk membuat password baru:\n%s\n\nLink berlaku 30 menit. Abaikan email ini jika kamu tidak meminta reset password.\n",
+			user.Name, actionLink("RESET_PASSWORD_PATH", "/reset-password", token)),
+	})
+}
+
+// ResetPassword sets the new password and logs out every session. Opening the link also
+// proves the mailbox belongs to the user, so the email counts as verified.
+func (u *AccountUsecase) ResetPassword(ctx context.Context, req model.ResetPasswordRequest) error {
+	userID, err := u.consumeToken(ctx, req.Token, model.TokenPurposeResetPassword)
+	if err != nil {
+		return err
+	}
+
+	hashedPass, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := u.UserRepo.UpdatePassword(ctx, userID, string(hashedPass)); err != nil {
+		return err
+	}
+	if err := u.SessionRepo.RevokeUserSessions(ctx, userID); err != nil {
+		return err
+	}
+	if err := u.UserRepo.MarkEmailVerified(ctx, userID); err != nil {
+		return err
+	}
+
+	if u.Attempts != nil {
+		user, err := u.UserRepo.FindByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		return u.Attempts.Unlock(ctx, user.Email, "password_reset")
+	}
+	return nil
+}
+
+// issueToken signs a new token and retires the older unused ones of the same purpose.
+func (u *AccountUsecase) issueToken(ctx context.Context, userID int, purpose string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	record := &model.ActionToken{
+		JTI:       ulid.Make().String(),
+		UserID:    userID,
+		Purpose:   purpose,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	claims := actionClaims{
+		UserID:  userID,
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        record.JTI,
+			Audience:  jwt.ClaimStrings{audienceAction},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(record.ExpiresAt),
+		},
+	}
+	signedToken, err := u.Keys.Sign(claims)
+	if err != nil {
+		return "", fmt.Errorf("Gagal generate token: %v", err)
+	}
+
+	if err := u.TokenRepo.InvalidateUnused(ctx, userID, purpose); err != nil {
+		return "", err
+	}
+	if err := u.TokenRepo.Create(ctx, record); err != nil {
+		return "", err
+	}
+	return signedToken, nil
+}
+
+func (u *AccountUsecase) consumeToken(ctx context.Context, tokenString, purpose string) (int, error) {
+	var claims actionClaims
+	token, err := u.Keys.Parse(tokenString, &claims, jwt.WithAudience(audienceAction))
+	if err != nil || !token.Valid || claims.Purpose != purpose || claims.ID == "" {
+		return 0, repository.ErrActionTokenInvalid
+	}
+
+	userID, err := u.TokenRepo.Consume(ctx, claims.ID, purpose)
+	if err != nil {
+		return 0, err
+	}
+	if userID != claims.UserID {
+		return 0, repository.ErrActionTokenInvalid
+	}
+	return userID, nil
+}
+
+// actionLink builds a mail link into the web app at APP_BASE_URL, not into this API: the page
+// at the path from pathEnv (defaultPath when unset) reads the token from the query and POSTs
+// it to /api/v1/email/verify or /api/v1/password/reset.
+func actionLink(pathEnv, defaultPath, token string) string {
+	base := os.Getenv("APP_BASE_URL")
+	if base == "" {
+		base = "http://localhost:8080"
+	}
+	path := os.Getenv(pathEnv)
+	if path == "" {
+		path = defaultPath
+	}
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(path, "/") + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,229 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"ewallet-service/internal/mailer"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"ewallet-service/internal/repository/mocks"
+	"ewallet-service/internal/usecase"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// mailerStub keeps sent messages instead of sending them
+type mailerStub struct {
+	sent []mailer.Message
+}
+
+func (m *mailerStub) Send(ctx context.Context, msg mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+var tokenInLink = regexp.MustCompile(`\?token=(\S+)`)
+
+func tokenFromMail(t *testing.T, msg mailer.Message) string {
+	match := tokenInLink.FindStringSubmatch(msg.Body)
+	if match == nil {
+		t.Fatalf("no token link in mail body: %q", msg.Body)
+	}
+	token, _ := url.QueryUnescape(match[1])
+	return token
+}
+
+func TestForgotPassword_UnknownEmailIsSilent(t *testing.T) {
+	// arrange
+	mockUser := new(mocks.UserRepositoryMock)
+	mail := &mailerStub{}
+	u := usecase.NewAccountUsecase(mockUser, new(mocks.ActionTokenRepositoryMock), new(mocks.SessionRepositoryMock), mail, testKeys)
+
+	mockUser.On("FindByEmail", mock.Anything, "nobody@example.com").Return(nil, sql.ErrNoRows)
+
+	// act
+	err := u.ForgotPassword(context.Background(), model.ForgotPasswordRequest{Email: "nobody@example.com"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, mail.sent)
+}
+
+func TestForgotPassword_LimitedPerEmail(t *testing.T) {
+	// arrange
+	mockUser := new(mocks.UserRepositoryMock)
+	mail := &mailerStub{}
+	u := usecase.NewAccountUsecase(mockUser, new(mocks.ActionTokenRepositoryMock), new(mocks.SessionRepositoryMock), mail, testKeys)
+	u.RateLimit = usecase.NewRateLimitUsecase(repository.NewMemoryRateLimitRepository())
+
+	mockUser.On("FindByEmail", mock.Anything, "nobody@example.com").Return(nil, sql.ErrNoRows)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, u.ForgotPassword(context.Background(), model.ForgotPasswordRequest{Email: "nobody@example.com"}))
+	}
+
+	// act: same address, different spelling
+	err := u.ForgotPassword(context.Background(), model.ForgotPasswordRequest{Email: "Nobody@Example.com"})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrRateLimited)
+	mockUser.AssertNumberOfCalls(t, "FindByEmail", 3)
+}
+
+func TestSendVerification_LinkPathIsConfigurable(t *testing.T) {
+	// arrange
+	t.Setenv("APP_BASE_URL", "https://app.example.com/")
+	t.Setenv("VERIFY_EMAIL_PATH", "/account/verify")
+	mockToken := new(mocks.ActionTokenRepositoryMock)
+	mail := &mailerStub{}
+	u := usecase.NewAccountUsecase(new(mocks.UserRepositoryMock), mockToken, new(mocks.SessionRepositoryMock), mail, testKeys)
+
+	mockToken.On("InvalidateUnused", mock.Anything, 7, model.TokenPurposeVerifyEmail).Return(nil)
+	mockToken.On("Create", mock.Anything, mock.AnythingOfType("*model.ActionToken")).Return(nil)
+
+	// act
+	err := u.SendVerification(context.Background(), &model.User{ID: 7, Name: "Budi", Email: "budi@example.com"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, mail.sent[0].Body, "https://app.example.com/account/verify?token=")
+}
+
+func TestResetPassword_FullFlow(t *testing.T) {
+	// arrange
+	mockUser := new(mocks.UserRepositoryMock)
+	mockToken := new(mocks.ActionTokenRepositoryMock)
+	mockSession := new(mocks.SessionRepositoryMock)
+	mail := &mailerStub{}
+	u := usecase.NewAccountUsecase(mockUser, mockToken, mockSession, mail, testKeys)
+
+	user := &model.User{ID: 7, Name: "Budi", Email: "budi@example.com"}
+	mockUser.On("FindByEmail", mock.Anything, "budi@example.com").Return(user, nil)
+	mockToken.On("InvalidateUnused", mock.Anything, 7, model.TokenPurposeResetPassword).Return(nil)
+
+	var jti string
+	mockToken.On("Create", mock.Anything, mock.AnythingOfType("*model.ActionToken")).Run(func(args mock.Arguments) {
+		jti = args.Get(1).(*model.ActionToken).JTI
+	}).Return(nil)
+
+	assert.NoError(t, u.ForgotPassword(context.Background(), model.ForgotPasswordRequest{Email: "budi@example.com"}))
+	u.Wait()
+	assert.Len(t, mail.sent, 1)
+	assert.Equal(t, "budi@example.com", mail.sent[0].To)
+
+	mockToken.On("Consume", mock.Anything, jti, model.TokenPurposeResetPassword).Return(7, nil)
+	mockUser.On("UpdatePassword", mock.Anything, 7, mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte("rahasia-baru")) == nil
+	})).Return(nil)
+	mockSession.On("RevokeUserSessions", mock.Anything, 7).Return(nil)
+	mockUser.On("MarkEmailVerified", mock.Anything, 7).Return(nil)
+
+	// act
+	err := u.ResetPassword(context.Background(), model.ResetPasswordRequest{
+		Token:       tokenFromMail(t, mail.sent[0]),
+		NewPassword: "rahasia-baru",
+	})
+
+	// assert
+	assert.NoError(t, err)
+	mockUser.AssertExpectations(t)
+	mockToken.AssertExpectations(t)
+	mockSession.AssertExpectations(t)
+}
+
+func TestResetPassword_RejectsVerificationToken(t *testing.T) {
+	// arrange
+	mockUser := new(mocks.UserRepositoryMock)
+	mockToken := new(mocks.ActionTokenRepositoryMock)
+	mail := &mailerStub{}
+	u := usecase.NewAccountUsecase(mockUser, mockToken, new(mocks.SessionRepositoryMock), mail, testKeys)
+
+	mockToken.On("InvalidateUnused", mock.Anything, 7, model.TokenPurposeVerifyEmail).Return(nil)
+	mockToken.On("Create", mock.Anything, mock.AnythingOfType("*model.ActionToken")).Return(nil)
+	assert.NoError(t, u.SendVerification(context.Background(), &model.User{ID: 7, Email: "budi@example.com"}))
+
+	// act: a verification link must not work as a reset link
+	err := u.ResetPassword(context.Background(), model.ResetPasswordRequest{
+		Token:       tokenFromMail(t, mail.sent[0]),
+		NewPassword: "rahasia-baru",
+	})
+
+	// assert
+	assert.ErrorIs(t, err, repository.ErrActionTokenInvalid)
+	mockToken.AssertNotCalled(t, "Consume", mock.Anything, mock.Anything, mock.Anything)
+	mockUser.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResetPassword_RejectsAccessToken(t *testing.T) {
+	// arrange
+	mockSession := new(mocks.SessionRepositoryMock)
+	mockToken := new(mocks.ActionTokenRepositoryMock)
+	u := usecase.NewAccountUsecase(new(mocks.UserRepositoryMock), mockToken, mockSession, &mailerStub{}, testKeys)
+
+	mockSession.On("CreateSession", mock.Anything, mock.AnythingOfType("*model.Session"), mock.AnythingOfType("string")).Return(nil)
+	tokens, _ := usecase.NewAuthUsecase(mockSession, testKeys).IssueTokens(context.Background(), &model.User{ID: 7})
+
+	// act
+	err := u.ResetPassword(context.Background(), model.ResetPasswordRequest{Token: tokens.AccessToken, NewPassword: "rahasia-baru"})
+
+	// assert
+	assert.ErrorIs(t, err, repository.ErrActionTokenInvalid)
+	mockToken.AssertNotCalled(t, "Consume", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVerifyEmail_UsedToken(t *testing.T) {
+	// arrange
+	mockUser := new(mocks.UserRepositoryMock)
+	mockToken := new(mocks.ActionTokenRepositoryMock)
+	mail := &mailerStub{}
+	u := usecase.NewAccountUsecase(mockUser, mockToken, new(mocks.SessionRepositoryMock), mail, testKeys)
+
+	mockToken.On("InvalidateUnused", mock.Anything, 7, model.TokenPurposeVerifyEmail).Return(nil)
+	mockToken.On("Create", mock.Anything, mock.AnythingOfType("*model.ActionToken")).Return(nil)
+	u.SendVerification(context.Background(), &model.User{ID: 7, Email: "budi@example.com"})
+
+	// the row was already consumed by an earlier click
+	mockToken.On("Consume", mock.Anything, mock.Anything, model.TokenPurposeVerifyEmail).Return(0, repository.ErrActionTokenInvalid)
+
+	// act
+	err := u.VerifyEmail(context.Background(), model.VerifyEmailRequest{Token: tokenFromMail(t, mail.sent[0])})
+
+	// assert
+	assert.ErrorIs(t, err, repository.ErrActionTokenInvalid)
+	mockUser.AssertNotCalled(t, "MarkEmailVerified", mock.Anything, mock.Anything)
+}
+
+func TestSendVerification_AlreadyVerified(t *testing.T) {
+	u := usecase.NewAccountUsecase(new(mocks.UserRepositoryMock), new(mocks.ActionTokenRepositoryMock), new(mocks.SessionRepositoryMock), &mailerStub{}, testKeys)
+
+	err := u.SendVerification(context.Background(), &model.User{ID: 7, EmailVerified: true})
+
+	assert.ErrorIs(t, err, usecase.ErrEmailAlreadyVerified)
+}
+
+func TestResendVerification_Limited(t *testing.T) {
+	// arrange
+	mockUser := new(mocks.UserRepositoryMock)
+	mockToken := new(mocks.ActionTokenRepositoryMock)
+	mail := &mailerStub{}
+	u := usecase.NewAccountUsecase(mockUser, mockToken, new(mocks.SessionRepositoryMock), mail, testKeys)
+	u.RateLimit = usecase.NewRateLimitUsecase(repository.NewMemoryRateLimitRepository())
+
+	mockUser.On("FindByID", mock.Anything, 7).Return(&model.User{ID: 7, Name: "Budi", Email: "budi@example.com"}, nil)
+	mockToken.On("InvalidateUnused", mock.Anything, 7, model.TokenPurposeVerifyEmail).Return(nil)
+	mockToken.On("Create", mock.Anything, mock.AnythingOfType("*model.ActionToken")).Return(nil)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, u.ResendVerification(context.Background(), 7))
+	}
+
+	// act
+	err := u.ResendVerification(context.Background(), 7)
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrRateLimited)
+	assert.Len(t, mail.sent, 3)
+}
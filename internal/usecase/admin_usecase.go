@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+const defaultUserSearchLimit = 20
+
+var (
+	ErrUserSearchEmpty       = errors.New("Isi minimal satu filter: email, name atau wallet_number")
+	ErrSelfAdministration    = errors.New("Tidak dapat membekukan atau mengubah akun sendiri")
+	ErrStaffAccount          = errors.New("Hanya admin yang dapat membekukan atau membatasi akun staf")
+	ErrAccountAlreadyFrozen  = errors.New("Akun sudah dibekukan")
+	ErrAccountNotFrozen      = errors.New("Akun tidak sedang dibekukan")
+	ErrWalletReactivation    = errors.New("Hanya admin yang dapat mengaktifkan kembali wallet")
+	ErrWalletCloseAdminOnly  = errors.New("Hanya admin yang dapat menutup wallet")
+	ErrWalletLoosenAdminOnly = errors.New("Hanya admin yang dapat melonggarkan atau mengganti pembatasan wallet")
+)
+
+// AdminUsecase is the back-office side: support staff look up any user or wallet and
+// freeze accounts, admins additionally unfreeze them and assign roles. Every change is audited.
+type AdminUsecase struct {
+	AdminRepo    repository.AdminRepository
+	SessionRepo  repository.SessionRepository
+	AuditRepo    repository.AuditRepository
+	Transactions *TransactionUsecase
+}
+
+func NewAdminUsecase(adminRepo repository.AdminRepository, sessionRepo repository.SessionRepository, auditRepo repository.AuditRepository, transactions *TransactionUsecase) *AdminUsecase {
+	return &AdminUsecase{AdminRepo: adminRepo, SessionRepo: sessionRepo, AuditRepo: auditRepo, Transactions: transactions}
+}
+
+func (u *AdminUsecase) SearchUsers(ctx context.Context, search model.UserSearch) ([]model.UserAccount, error) {
+	if search.Email == "" && search.Name == "" && search.WalletNumber == "" {
+		return nil, ErrUserSearchEmpty
+	}
+	if search.Limit <= 0 {
+		search.Limit = defaultUserSearchLimit
+	}
+	return u.AdminRepo.SearchUsers(ctx, search)
+}
+
+func (u *AdminUsecase) GetUser(ctx context.Context, userID int) (*model.UserAccount, error) {
+	return u.AdminRepo.FindUserAccount(ctx, userID)
+}
+
+func (u *AdminUsecase) GetWallet(ctx context.Context, walletNumber string) (*model.UserAccount, error) {
+	return u.AdminRepo.FindAccountByWalletNumber(ctx, walletNumber)
+}
+
+// WalletHistory is GET /transactions of the owner narrowed to this one pocket, with the same filters and paging.
+func (u *AdminUsecase) WalletHistory(ctx context.Context, walletNumber string, filter model.TransactionFilter) (model.TransactionPage, error) {
+	account, err := u.AdminRepo.FindAccountByWalletNumber(ctx, walletNumber)
+	if err != nil {
+		return model.TransactionPage{}, err
+	}
+	filter.WalletID = account.Wallet.ID
+	return u.Transactions.GetHistory(ctx, account.ID, filter)
+}
+
+// Freeze blocks logins and token refreshes and ends every session of the user, which also
+// rejects the access tokens already out.
+func (u *AdminUsecase) Freeze(ctx context.Context, actor *model.AccessClaims, userID int, req model.FreezeAccountRequest) error {
+	account, err := u.manageableAccount(ctx, actor, userID)
+	if err != nil {
+		return err
+	}
+	if account.FrozenAt != nil {
+		return ErrAccountAlreadyFrozen
+	}
+	if isStaffRole(account.Role) && actor.Role != model.RoleAdmin {
+		return ErrStaffAccount
+	}
+
+	if err := u.AdminRepo.SetFrozen(ctx, userID, &req.Reason); err != nil {
+		return err
+	}
+	if err := u.SessionRepo.RevokeUserSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	u.audit(ctx, actor, model.AuditAccountFrozen, userID, req.Reason)
+	return nil
+}
+
+func (u *AdminUsecase) Unfreeze(ctx context.Context, actor *model.AccessClaims, userID int) error {
+	account, err := u.manageableAccount(ctx, actor, userID)
+	if err != nil {
+		return err
+	}
+	if account.FrozenAt == nil {
+		return ErrAccountNotFrozen
+	}
+
+	if err := u.AdminRepo.SetFrozen(ctx, userID, nil); err != nil {
+		return err
+	}
+
+	u.audit(ctx, actor, model.AuditAccountUnfrozen, userID, "")
+	return nil
+}
+
+// ChangeRole ends every session of the user: access tokens carry the role, so the old one
+// must not outlive the change. The new role applies from the next login.
+func (u *AdminUsecase) ChangeRole(ctx context.Context, actor *model.AccessClaims, userID int, req model.ChangeRoleRequest) (*model.UserAccount, error) {
+	account, err := u.manageableAccount(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Role == req.Role {
+		return account, nil
+	}
+
+	if err := u.AdminRepo.SetRole(ctx, userID, req.Role); err != nil {
+		return nil, err
+	}
+	if err := u.SessionRepo.RevokeUserSessions(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	u.audit(ctx, actor, model.AuditRoleChanged, userID, fmt.Sprintf("%s -> %s", account.Role, req.Role))
+	account.Role = req.Role
+	return account, nil
+}
+
+// ChangeWalletStatus restricts, reactivates or closes a wallet; top-ups and transfers check it under their
+// row lock. A restriction or reactivation applies to every pocket of the owner that is not closed, as money
+// would otherwise just move on through a pocket left open. Closing is final and only affects the named
+// wallet, which must be empty. Support may only tighten a status (see model.WalletStatusTightens), so
+// nothing leaves frozen without an admin, and only admins may touch staff wallets; only admins may close
+// wallets, lift a restriction or swap one for another.
+func (u *AdminUsecase) ChangeWalletStatus(ctx context.Context, actor *model.AccessClaims, walletNumber string, req model.ChangeWalletStatusRequest) (*model.Wallet, error) {
+	account, err := u.AdminRepo.FindAccountByWalletNumber(ctx, walletNumber)
+	if err != nil {
+		return nil, err
+	}
+	if account.ID == actor.UserID {
+		return nil, ErrSelfAdministration
+	}
+
+	if isStaffRole(account.Role) && actor.Role != model.RoleAdmin {
+		return nil, ErrStaffAccount
+	}
+
+	wallet := account.Wallet
+	if wallet.Status == model.WalletClosed {
+		return nil, repository.ErrWalletClosed
+	}
+
+	pockets := account.Pockets
+	if req.Status == model.WalletClosed {
+		if actor.Role != model.RoleAdmin {
+			return nil, ErrWalletCloseAdminOnly
+		}
+		if !wallet.Balance.IsZero() {
+			return nil, repository.ErrWalletNotEmpty
+		}
+		pockets = []model.Wallet{*wallet}
+	}
+
+	var walletIDs []int
+	var changes []string
+	for _, pocket := range pockets {
+		if pocket.Status == model.WalletClosed || pocket.Status == req.Status {
+			continue
+		}
+		if actor.Role != model.RoleAdmin {
+			if err := supportMayChange(pocket.Status, req.Status); err != nil {
+				return nil, err
+			}
+		}
+		walletIDs = append(walletIDs, pocket.ID)
+		changes = append(changes, pocket.WalletNumber+": "+pocket.Status)
+	}
+	if len(walletIDs) == 0 {
+		return wallet, nil
+	}
+
+	if err := u.AdminRepo.SetWalletStatus(ctx, walletIDs, req.Status, req.Reason); err != nil {
+		return nil, err
+	}
+
+	u.audit(ctx, actor, model.AuditWalletStatus, account.ID, fmt.Sprintf("wallets %s -> %s, %s", strings.Join(changes, ", "), req.Status, req.Reason))
+	wallet.Status = req.Status
+	return wallet, nil
+}
+
+func supportMayChange(from, to string) error {
+	switch {
+	case to == model.WalletActive:
+		return ErrWalletReactivation
+	case !model.WalletStatusTightens(from, to):
+		return ErrWalletLoosenAdminOnly
+	}
+	return nil
+}
+
+// staff must not lock themselves out or hand themselves a role
+func (u *AdminUsecase) manageableAccount(ctx context.Context, actor *model.AccessClaims, userID int) (*model.UserAccount, error) {
+	if actor.UserID == userID {
+		return nil, ErrSelfAdministration
+	}
+	return u.AdminRepo.FindUserAccount(ctx, userID)
+}
+
+// like the login audit, a failed write is logged rather than undoing the action
+func (u *AdminUsecase) audit(ctx context.Context, actor *model.AccessClaims, event string, userID int, detail string) {
+	entry := &model.AuditEntry{
+		Event:   event,
+		Actor:   "user:" + strconv.Itoa(actor.UserID),
+		Subject: "user:" + strconv.Itoa(userID),
+		Detail:  detail,
+	}
+	if err := u.AuditRepo.Record(ctx, entry); err != nil {
+		log.Printf("Gagal mencatat audit %s untuk %s: %v", entry.Event, entry.Subject, err)
+	}
+}
+
+func isStaffRole(role string) bool {
+	return role == model.RoleSupport || role == model.RoleAdmin
+}
@@ -0,0 +1,327 @@
+package usecase_test
+
+import (
+	"context"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"ewallet-service/internal/repository/mocks"
+	"ewallet-service/internal/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type adminFixture struct {
+	admin   *mocks.AdminRepositoryMock
+	session *mocks.SessionRepositoryMock
+	audit   *mocks.AuditRepositoryMock
+	trx     *mocks.TransactionRepositoryMock
+	u       *usecase.AdminUsecase
+}
+
+func newAdminFixture() adminFixture {
+	f := adminFixture{
+		admin:   new(mocks.AdminRepositoryMock),
+		session: new(mocks.SessionRepositoryMock),
+		audit:   new(mocks.AuditRepositoryMock),
+		trx:     new(mocks.TransactionRepositoryMock),
+	}
+	f.u = usecase.NewAdminUsecase(f.admin, f.session, f.audit, usecase.NewTransactionUsecase(f.trx, verifierStub{}))
+	return f
+}
+
+var (
+	supportClaims = &model.AccessClaims{UserID: 100, Role: model.RoleSupport}
+	adminClaims   = &model.AccessClaims{UserID: 1, Role: model.RoleAdmin}
+)
+
+func TestAdminFreeze_RevokesSessionsAndAudits(t *testing.T) {
+	// arrange
+	f := newAdminFixture()
+	f.admin.On("FindUserAccount", mock.Anything, 5).Return(&model.UserAccount{ID: 5, Role: model.RoleCustomer}, nil)
+	f.admin.On("SetFrozen", mock.Anything, 5, mock.MatchedBy(func(r *string) bool { return r != nil && *r == "laporan penipuan" })).Return(nil)
+	f.session.On("RevokeUserSessions", mock.Anything, 5).Return(nil)
+	f.audit.On("Record", mock.Anything, mock.AnythingOfType("*model.AuditEntry")).Return(nil)
+
+	// act
+	err := f.u.Freeze(context.Background(), supportClaims, 5, model.FreezeAccountRequest{Reason: "laporan penipuan"})
+
+	// assert
+	assert.NoError(t, err)
+	f.admin.AssertExpectations(t)
+	f.session.AssertExpectations(t)
+	entry := f.audit.Calls[0].Arguments.Get(1).(*model.AuditEntry)
+	assert.Equal(t, model.AuditAccountFrozen, entry.Event)
+	assert.Equal(t, "user:100", entry.Actor)
+	assert.Equal(t, "user:5", entry.Subject)
+}
+
+func TestAdminFreeze_Self(t *testing.T) {
+	f := newAdminFixture()
+
+	err := f.u.Freeze(context.Background(), supportClaims, supportClaims.UserID, model.FreezeAccountRequest{Reason: "x"})
+
+	assert.ErrorIs(t, err, usecase.ErrSelfAdministration)
+	f.admin.AssertNotCalled(t, "SetFrozen", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminFreeze_SupportCannotFreezeStaff(t *testing.T) {
+	// arrange
+	f := newAdminFixture()
+	f.admin.On("FindUserAccount", mock.Anything, 2).Return(&model.UserAccount{ID: 2, Role: model.RoleAdmin}, nil)
+
+	// act
+	err := f.u.Freeze(context.Background(), supportClaims, 2, model.FreezeAccountRequest{Reason: "x"})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrStaffAccount)
+	f.admin.AssertNotCalled(t, "SetFrozen", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminUnfreeze_NotFrozen(t *testing.T) {
+	f := newAdminFixture()
+	f.admin.On("FindUserAccount", mock.Anything, 5).Return(&model.UserAccount{ID: 5, Role: model.RoleCustomer}, nil)
+
+	err := f.u.Unfreeze(context.Background(), &model.AccessClaims{UserID: 1, Role: model.RoleAdmin}, 5)
+
+	assert.ErrorIs(t, err, usecase.ErrAccountNotFrozen)
+}
+
+func TestAdminUnfreeze_Success(t *testing.T) {
+	// arrange
+	f := newAdminFixture()
+	frozenAt := time.Now().Add(-time.Hour)
+	f.admin.On("FindUserAccount", mock.Anything, 5).Return(&model.UserAccount{ID: 5, FrozenAt: &frozenAt}, nil)
+	f.admin.On("SetFrozen", mock.Anything, 5, (*string)(nil)).Return(nil)
+	f.audit.On("Record", mock.Anything, mock.AnythingOfType("*model.AuditEntry")).Return(nil)
+
+	// act
+	err := f.u.Unfreeze(context.Background(), &model.AccessClaims{UserID: 1, Role: model.RoleAdmin}, 5)
+
+	// assert
+	assert.NoError(t, err)
+	f.admin.AssertExpectations(t)
+	assert.Equal(t, model.AuditAccountUnfrozen, f.audit.Calls[0].Arguments.Get(1).(*model.AuditEntry).Event)
+}
+
+func TestAdminChangeRole_Audited(t *testing.T) {
+	// arrange
+	f := newAdminFixture()
+	f.admin.On("FindUserAccount", mock.Anything, 5).Return(&model.UserAccount{ID: 5, Role: model.RoleCustomer}, nil)
+	f.admin.On("SetRole", mock.Anything, 5, model.RoleMerchant).Return(nil)
+	f.session.On("RevokeUserSessions", mock.Anything, 5).Return(nil)
+	f.audit.On("Record", mock.Anything, mock.AnythingOfType("*model.AuditEntry")).Return(nil)
+
+	// act
+	res, err := f.u.ChangeRole(context.Background(), &model.AccessClaims{UserID: 1, Role: model.RoleAdmin}, 5, model.ChangeRoleRequest{Role: model.RoleMerchant})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, model.RoleMerchant, res.Role)
+	f.session.AssertExpectations(t)
+	entry := f.audit.Calls[0].Arguments.Get(1).(*model.AuditEntry)
+	assert.Equal(t, model.AuditRoleChanged, entry.Event)
+	assert.Equal(t, "customer -> merchant", entry.Detail)
+}
+
+func TestAdminWalletHistory_OnlyThatPocket(t *testing.T) {
+	// arrange
+	f := newAdminFixture()
+	f.admin.On("FindAccountByWalletNumber", mock.Anything, "8001234567").Return(walletAccount(model.WalletActive), nil)
+	onlyPocket := mock.MatchedBy(func(filter model.TransactionFilter) bool { return filter.WalletID == 3 })
+	f.trx.On("GetTransactionHistory", mock.Anything, 9, onlyPocket).Return([]model.Transaction{{ID: 1, WalletID: 3}}, nil)
+
+	// act
+	page, err := f.u.WalletHistory(context.Background(), "8001234567", model.TransactionFilter{})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	f.trx.AssertExpectations(t)
+}
+
+func TestAdminWalletHistory_UnknownWallet(t *testing.T) {
+	f := newAdminFixture()
+	f.admin.On("FindAccountByWalletNumber", mock.Anything, "0000").Return(nil, repository.ErrWalletNotFound)
+
+	_, err := f.u.WalletHistory(context.Background(), "0000", model.TransactionFilter{})
+
+	assert.ErrorIs(t, err, repository.ErrWalletNotFound)
+	f.trx.AssertNotCalled(t, "GetTransactionHistory", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminSearchUsers_NeedsFilter(t *testing.T) {
+	f := newAdminFixture()
+
+	_, err := f.u.SearchUsers(context.Background(), model.UserSearch{})
+
+	assert.ErrorIs(t, err, usecase.ErrUserSearchEmpty)
+}
+
+func walletAccount(status string) *model.UserAccount {
+	wallet := model.Wallet{ID: 3, UserID: 9, WalletNumber: "8001234567", IsPrimary: true, Status: status}
+	return &model.UserAccount{ID: 9, Wallet: &wallet, Pockets: []model.Wallet{wallet}}
+}
+
+func TestAdminChangeWalletStatus_SupportFreezes(t *testing.T) {
+	// arrange
+	f := newAdminFixture()
+	f.admin.On("FindAccountByWalletNumber", mock.Anything, "8001234567").Return(walletAccount(model.WalletActive), nil)
+	f.admin.On("SetWalletStatus", mock.Anything, []int{3}, model.WalletFrozen, "akun diretas").Return(nil)
+	f.audit.On("Record", mock.Anything, mock.AnythingOfType("*model.AuditEntry")).Return(nil)
+
+	// act
+	wallet, err := f.u.ChangeWalletStatus(context.Background(), supportClaims, "8001234567", model.ChangeWalletStatusRequest{Status: model.WalletFrozen, Reason: "akun diretas"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, model.WalletFrozen, wallet.Status)
+	f.admin.AssertExpectations(t)
+	entry := f.audit.Calls[0].Arguments.Get(1).(*model.AuditEntry)
+	assert.Equal(t, model.AuditWalletStatus, entry.Event)
+	assert.Equal(t, "user:9", entry.Subject)
+}
+
+func TestAdminChangeWalletStatus_CoversAllPockets(t *testing.T) {
+	// arrange
+	f := newAdminFixture()
+	account := walletAccount(model.WalletActive)
+	account.Pockets = append(account.Pockets,
+		model.Wallet{ID: 4, UserID: 9, WalletNumber: "8001234568", Status: model.WalletDebitBlocked},
+		model.Wallet{ID: 5, UserID: 9, WalletNumber: "8001234569", Status: model.WalletClosed},
+		model.Wallet{ID: 6, UserID: 9, WalletNumber: "8001234570", Status: model.WalletFrozen},
+	)
+	f.admin.On("FindAccountByWalletNumber", mock.Anything, "8001234567").Return(account, nil)
+	f.admin.On("SetWalletStatus", mock.Anything, []int{3, 4}, model.WalletFrozen, "akun diretas").Return(nil)
+	f.audit.On("Record", mock.Anything, mock.AnythingOfType("*model.AuditEntry")).Return(nil)
+
+	// act
+	_, err := f.u.ChangeWalletStatus(context.Background(), supportClaims, "8001234567", model.ChangeWalletStatusRequest{Status: model.WalletFrozen, Reason: "akun diretas"})
+
+	// assert
+	assert.NoError(t, err)
+	f.admin.AssertExpectations(t)
+	entry := f.audit.Calls[0].Arguments.Get(1).(*model.AuditEntry)
+	assert.Contains(t, entry.Detail, "8001234568: debit_blocked")
+}
+
+func TestAdminChangeWalletStatus_SupportCannotLoosenAnotherPocket(t *testing.T) {
+	f := newAdminFixture()
+	account := walletAccount(model.WalletActive)
+	account.Pockets = append(account.Pockets, model.Wallet{ID: 4, UserID: 9, WalletNumber: "8001234568", Status: model.WalletFrozen})
+	f.admin.On("FindAccountByWalletNumber", mock.Anything, "8001234567").Return(account, nil)
+
+	_, err := f.u.ChangeWalletStatus(context.Background(), supportClaims, "8001234567", model.ChangeWalletStatusRequest{Status: model.WalletDebitBlocked, Reason: "x"})
+
+	assert.ErrorIs(t, err, usecase.ErrWalletLoosenAdminOnly)
+	f.admin.AssertNotCalled(t, "SetWalletStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminChangeWalletStatus_CloseOnlyNamedEmptyWallet(t *testing.T) {
+	// arrange
+	f := newAdminFixture()
+	account := walletAccount(model.WalletActive)
+	account.Pockets = append(account.Pockets, model.Wallet{ID: 4, UserID: 9, WalletNumber: "8001234568", Status: model.WalletActive})
+	f.admin.On("FindAccountByWalletNumber", mock.Anything, "8001234567").Return(account, nil)
+	f.admin.On("SetWalletStatus", mock.Anything, []int{3}, model.WalletClosed, "permintaan nasabah").Return(nil)
+	f.audit.On("Record", mock.Anything, mock.AnythingOfType("*model.AuditEntry")).Return(nil)
+
+	// act
+	wallet, err := f.u.ChangeWalletStatus(context.Background(), adminClaims, "8001234567", model.ChangeWalletStatusRequest{Status: model.WalletClosed, Reason: "permintaan nasabah"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, model.WalletClosed, wallet.Status)
+	f.admin.AssertExpectations(t)
+}
+
+func TestAdminChangeWalletStatus_CloseRefusedWithBalance(t *testing.T) {
+	f := newAdminFixture()
+	account := walletAccount(model.WalletActive)
+	account.Wallet.Balance = model.NewMoney(15000)
+	f.admin.On("FindAccountByWalletNumber", mock.Anything, "8001234567").Return(account, nil)
+
+	_, err := f.u.ChangeWalletStatus(context.Background(), adminClaims, "8001234567", model.ChangeWalletStatusRequest{Status: model.WalletClosed, Reason: "x"})
+
+	assert.ErrorIs(t, err, repository.ErrWalletNotEmpty)
+	f.admin.AssertNotCalled(t, "SetWalletStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminChangeWalletStatus_SupportCannotRestrictStaff(t *testing.T) {
+	f := newAdminFixture()
+	account := walletAccount(model.WalletActive)
+	account.Role = model.RoleSupport
+	f.admin.On("FindAccountByWalletNumber", mock.Anything, "8001234567").Return(account, nil)
+
+	_, err := f.u.ChangeWalletStatus(context.Background(), supportClaims, "8001234567", model.ChangeWalletStatusRequest{Status: model.WalletFrozen, Reason: "x"})
+
+	assert.ErrorIs(t, err, usecase.ErrStaffAccount)
+	f.admin.AssertNotCalled(t, "SetWalletStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminChangeWalletStatus_SupportCannotReactivate(t *testing.T) {
+	f := newAdminFixture()
+	f.admin.On("FindAccountByWalletNumber", mock.Anything, "8001234567").Return(walletAccount(model.WalletFrozen), nil)
+
+	_, err := f.u.ChangeWalletStatus(context.Background(), supportClaims, "8001234567", model.ChangeWalletStatusRequest{Status: model.WalletActive, Reason: "x"})
+
+	assert.ErrorIs(t, err, usecase.ErrWalletReactivation)
+	f.admin.AssertNotCalled(t, "SetWalletStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminChangeWalletStatus_SupportCannotLeaveFrozen(t *testing.T) {
+	f := newAdminFixture()
+	f.admin.On("FindAccountByWalletNumber", mock.Anything, "8001234567").Return(walletAccount(model.WalletFrozen), nil)
+
+	_, err := f.u.ChangeWalletStatus(context.Background(), supportClaims, "8001234567", model.ChangeWalletStatusRequest{Status: model.WalletCreditBlocked, Reason: "x"})
+
+	assert.ErrorIs(t, err, usecase.ErrWalletLoosenAdminOnly)
+	f.admin.AssertNotCalled(t, "SetWalletStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminChangeWalletStatus_SupportOnlyTightens(t *testing.T) {
+	cases := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{model.WalletActive, model.WalletDebitBlocked, true},
+		{model.WalletActive, model.WalletCreditBlocked, true},
+		{model.WalletDebitBlocked, model.WalletFrozen, true},
+		{model.WalletCreditBlocked, model.WalletFrozen, true},
+		{model.WalletDebitBlocked, model.WalletCreditBlocked, false},
+		{model.WalletCreditBlocked, model.WalletDebitBlocked, false},
+		{model.WalletFrozen, model.WalletDebitBlocked, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.from+" -> "+tc.to, func(t *testing.T) {
+			// arrange
+			f := newAdminFixture()
+			f.admin.On("FindAccountByWalletNumber", mock.Anything, "8001234567").Return(walletAccount(tc.from), nil)
+			f.admin.On("SetWalletStatus", mock.Anything, []int{3}, tc.to, "x").Return(nil)
+			f.audit.On("Record", mock.Anything, mock.AnythingOfType("*model.AuditEntry")).Return(nil)
+
+			// act
+			_, err := f.u.ChangeWalletStatus(context.Background(), supportClaims, "8001234567", model.ChangeWalletStatusRequest{Status: tc.to, Reason: "x"})
+
+			// assert
+			if tc.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, usecase.ErrWalletLoosenAdminOnly)
+			}
+		})
+	}
+}
+
+func TestAdminChangeWalletStatus_ClosedIsFinal(t *testing.T) {
+	f := newAdminFixture()
+	f.admin.On("FindAccountByWalletNumber", mock.Anything, "8001234567").Return(walletAccount(model.WalletClosed), nil)
+
+	_, err := f.u.ChangeWalletStatus(context.Background(), adminClaims, "8001234567", model.ChangeWalletStatusRequest{Status: model.WalletActive, Reason: "x"})
+
+	assert.ErrorIs(t, err, repository.ErrWalletClosed)
+	f.admin.AssertNotCalled(t, "SetWalletStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"ewallet-service/internal/jwtkeys"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	accessTokenTTL    = 15 * time.Minute
+	refreshTokenTTL   = 30 * 24 * time.Hour
+	challengeTokenTTL = 5 * time.Minute
+
+	challengePurpose = "login_2fa"
+)
+
+// Every kind of token is signed with the same keys, so each carries its own audience and is
+// only accepted where that audience is expected: a challenge or a mailed link never passes
+// as an access token, nor the other way round.
+const (
+	audienceAccess    = "ewallet:access"
+	audienceChallenge = "ewallet:login_2fa"
+	audienceAction    = "ewallet:account_action"
+)
+
+var (
+	ErrTokenInvalid = errors.New("Token tidak valid atau kadaluarsa")
+	ErrTokenRevoked = errors.New("Token sudah dicabut, silakan login ulang")
+)
+
+type accessClaims struct {
+	UserID    int    `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// tokens issued before roles existed carry none, their owners were all customers
+func (c accessClaims) roleOrDefault() string {
+	if c.Role == "" {
+		return model.RoleCustomer
+	}
+	return c.Role
+}
+
+// challengeClaims is the token between the password step and the OTP step of a 2FA login.
+// It has no session id, so it is never accepted as an access token.
+type challengeClaims struct {
+	UserID  int    `json:"user_id"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// AuthUsecase issues short-lived access tokens and rotating refresh tokens, and knows
+// which access tokens were revoked before they expired.
+type AuthUsecase struct {
+	SessionRepo repository.SessionRepository
+	Keys        *jwtkeys.KeySet
+}
+
+func NewAuthUsecase(repo repository.SessionRepository, keys *jwtkeys.KeySet) *AuthUsecase {
+	return &AuthUsecase{SessionRepo: repo, Keys: keys}
+}
+
+// IssueTokens starts a new session for the user.
+func (u *AuthUsecase) IssueTokens(ctx context.Context, user *model.User) (model.LoginResponse, error) {
+	session := &model.Session{
+		ID:        ulid.Make().String(),
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}
+
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return model.LoginResponse{}, err
+	}
+
+	if err := u.SessionRepo.CreateSession(ctx, session, hashToken(refreshToken)); err != nil {
+		return model.LoginResponse{}, fmt.Errorf("Gagal membuat sesi: %w", err)
+	}
+
+	return u.tokenResponse(session, refreshToken)
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh token.
+// The old refresh token cannot be used again.
+func (u *AuthUsecase) Refresh(ctx context.Context, refreshToken string) (model.LoginResponse, error) {
+	newToken, err := newRefreshToken()
+	if err != nil {
+		return model.LoginResponse{}, err
+	}
+
+	session, err := u.SessionRepo.RotateRefreshToken(ctx, hashToken(refreshToken), hashToken(newToken))
+	if err != nil {
+		return model.LoginResponse{}, err
+	}
+
+	return u.tokenResponse(session, newToken)
+}
+
+// Logout ends the session of the token and revokes the token itself.
+func (u *AuthUsecase) Logout(ctx context.Context, claims *model.AccessClaims) error {
+	if err := u.SessionRepo.RevokeSession(ctx, claims.SessionID); err != nil {
+		return err
+	}
+	return u.SessionRepo.RevokeToken(ctx, claims.JTI, claims.ExpiresAt)
+}
+
+// ParseAccessToken validates the signature and expiry of an access token and rejects revoked ones:
+// the token itself may be revoked, or the session it belongs to, which also ends every other
+// access token issued for that session.
+func (u *AuthUsecase) ParseAccessToken(ctx context.Context, tokenString string) (*model.AccessClaims, error) {
+	var claims accessClaims
+	token, err := u.Keys.Parse(tokenString, &claims, jwt.WithAudience(audienceAccess))
+	if err != nil || !token.Valid || claims.ID == "" || claims.SessionID == "" || claims.ExpiresAt == nil {
+		return nil, ErrTokenInvalid
+	}
+
+	revoked, err := u.SessionRepo.IsTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+
+	revoked, err = u.SessionRepo.IsSessionRevoked(ctx, claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+
+	return &model.AccessClaims{
+		UserID:    claims.UserID,
+		Email:     claims.Email,
+		Role:      claims.roleOrDefault(),
+		SessionID: claims.SessionID,
+		JTI:       claims.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+// PurgeRevokedTokens forgets revoked access tokens that have expired; the expiry check alone
+// rejects them from then on.
+func (u *AuthUsecase) PurgeRevokedTokens(ctx context.Context) (int64, error) {
+	return u.SessionRepo.PurgeRevokedTokens(ctx)
+}
+
+// IssueChallenge returns a short-lived token proving the password step of a 2FA login passed.
+func (u *AuthUsecase) IssueChallenge(user *model.User) (string, error) {
+	now := time.Now()
+	claims := challengeClaims{
+		UserID:  user.ID,
+		Purpose: challengePurpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        ulid.Make().String(),
+			Audience:  jwt.ClaimStrings{audienceChallenge},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(challengeTokenTTL)),
+		},
+	}
+
+	signedToken, err := u.Keys.Sign(claims)
+	if err != nil {
+		return "", fmt.Errorf("Gagal generate token: %v", err)
+	}
+	return signedToken, nil
+}
+
+// ParseChallenge validates a challenge token. It does not spend it, see ConsumeChallenge.
+func (u *AuthUsecase) ParseChallenge(tokenString string) (*model.ChallengeClaims, error) {
+	var claims challengeClaims
+	token, err := u.Keys.Parse(tokenString, &claims, jwt.WithAudience(audienceChallenge))
+	if err != nil || !token.Valid || claims.Purpose != challengePurpose || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil, ErrTokenInvalid
+	}
+	return &model.ChallengeClaims{
+		UserID:    claims.UserID,
+		JTI:       claims.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+// ConsumeChallenge spends a challenge token once the second factor passed: it is
+// recorded as revoked and a second login with it fails, even one racing this one.
+func (u *AuthUsecase) ConsumeChallenge(ctx context.Context, challenge *model.ChallengeClaims) error {
+	first, err := u.SessionRepo.ConsumeToken(ctx, challenge.JTI, challenge.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	if !first {
+		return ErrTokenInvalid
+	}
+	return nil
+}
+
+func (u *AuthUsecase) tokenResponse(session *model.Session, refreshToken string) (model.LoginResponse, error) {
+	now := time.Now()
+	claims := accessClaims{
+		UserID:    session.UserID,
+		Email:     session.Email,
+		Role:      session.Role,
+		SessionID: session.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        ulid.Make().String(),
+			Audience:  jwt.ClaimStrings{audienceAccess},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTokenTTL)),
+		},
+	}
+
+	// signed with the current key, its kid goes into the header
+	signedToken, err := u.Keys.Sign(claims)
+	if err != nil {
+		return model.LoginResponse{}, fmt.Errorf("Gagal generate token: %v", err)
+	}
+
+	return model.LoginResponse{
+		AccessToken:      signedToken,
+		Type:             "Bearer",
+		ExpiresIn:        formatTTL(accessTokenTTL),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: formatTTL(refreshTokenTTL),
+	}, nil
+}
+
+// formatTTL prints 15m / 720h instead of 15m0s / 720h0m0s
+func formatTTL(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
+
+// refresh tokens are random and opaque, only their SHA-256 is stored
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
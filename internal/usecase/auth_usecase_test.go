@@ -0,0 +1,272 @@
+package usecase_test
+
+import (
+	"context"
+	"ewallet-service/internal/jwtkeys"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"ewallet-service/internal/repository/mocks"
+	"ewallet-service/internal/usecase"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// testKeys signs every token in the usecase tests
+var testKeys = func() *jwtkeys.KeySet {
+	key, err := jwtkeys.GenerateEd25519("test")
+	if err != nil {
+		panic(err)
+	}
+	ks, err := jwtkeys.New(key)
+	if err != nil {
+		panic(err)
+	}
+	return ks
+}()
+
+func issueTestTokens(t *testing.T, mockSession *mocks.SessionRepositoryMock, u *usecase.AuthUsecase) model.LoginResponse {
+	mockSession.On("CreateSession", mock.Anything, mock.AnythingOfType("*model.Session"), mock.AnythingOfType("string")).Return(nil).Once()
+	res, err := u.IssueTokens(context.Background(), &model.User{ID: 1, Email: "test@example.com"})
+	assert.NoError(t, err)
+	return res
+}
+
+// mockActiveToken lets every access token through the revocation checks
+func mockActiveToken(mockSession *mocks.SessionRepositoryMock) {
+	mockSession.On("IsTokenRevoked", mock.Anything, mock.AnythingOfType("string")).Return(false, nil)
+	mockSession.On("IsSessionRevoked", mock.Anything, mock.AnythingOfType("string")).Return(false, nil)
+}
+
+func TestRefresh_RotatesToken(t *testing.T) {
+	// arrange
+	mockSession := new(mocks.SessionRepositoryMock)
+	u := usecase.NewAuthUsecase(mockSession, testKeys)
+	login := issueTestTokens(t, mockSession, u)
+
+	session := &model.Session{ID: "01JAZ3N6W5Q2K8X4T7R9M1B0CD", UserID: 1, Email: "test@example.com", ExpiresAt: time.Now().Add(time.Hour)}
+	mockSession.On("RotateRefreshToken", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(session, nil)
+
+	// act
+	res, err := u.Refresh(context.Background(), login.RefreshToken)
+
+	// assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, res.AccessToken)
+	assert.NotEqual(t, login.RefreshToken, res.RefreshToken)
+
+	// old and new token are stored hashed, never in plain text
+	oldHash := mockSession.Calls[1].Arguments.String(1)
+	newHash := mockSession.Calls[1].Arguments.String(2)
+	assert.Len(t, oldHash, 64)
+	assert.NotEqual(t, login.RefreshToken, oldHash)
+	assert.NotEqual(t, oldHash, newHash)
+}
+
+func TestRefresh_ReusedToken(t *testing.T) {
+	// arrange
+	mockSession := new(mocks.SessionRepositoryMock)
+	u := usecase.NewAuthUsecase(mockSession, testKeys)
+
+	mockSession.On("RotateRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(nil, repository.ErrRefreshTokenReused)
+
+	// act
+	res, err := u.Refresh(context.Background(), "already-used")
+
+	// assert
+	assert.ErrorIs(t, err, repository.ErrRefreshTokenReused)
+	assert.Empty(t, res.AccessToken)
+}
+
+func TestParseAccessToken_Valid(t *testing.T) {
+	// arrange
+	mockSession := new(mocks.SessionRepositoryMock)
+	u := usecase.NewAuthUsecase(mockSession, testKeys)
+	login := issueTestTokens(t, mockSession, u)
+
+	mockActiveToken(mockSession)
+
+	// act
+	claims, err := u.ParseAccessToken(context.Background(), login.AccessToken)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, claims.UserID)
+	assert.NotEmpty(t, claims.JTI)
+	assert.NotEmpty(t, claims.SessionID)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), claims.ExpiresAt, 5*time.Second)
+}
+
+func TestParseAccessToken_CarriesRole(t *testing.T) {
+	// arrange
+	mockSession := new(mocks.SessionRepositoryMock)
+	u := usecase.NewAuthUsecase(mockSession, testKeys)
+
+	mockSession.On("CreateSession", mock.Anything, mock.AnythingOfType("*model.Session"), mock.AnythingOfType("string")).Return(nil).Once()
+	login, err := u.IssueTokens(context.Background(), &model.User{ID: 7, Email: "cs@example.com", Role: model.RoleSupport})
+	assert.NoError(t, err)
+	mockActiveToken(mockSession)
+
+	// act
+	claims, err := u.ParseAccessToken(context.Background(), login.AccessToken)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, model.RoleSupport, claims.Role)
+}
+
+func TestRefresh_TakesCurrentRole(t *testing.T) {
+	// arrange
+	mockSession := new(mocks.SessionRepositoryMock)
+	u := usecase.NewAuthUsecase(mockSession, testKeys)
+	login := issueTestTokens(t, mockSession, u)
+
+	// promoted after the first login
+	session := &model.Session{ID: "01JAZ3N6W5Q2K8X4T7R9M1B0CD", UserID: 1, Email: "test@example.com", Role: model.RoleAdmin, ExpiresAt: time.Now().Add(time.Hour)}
+	mockSession.On("RotateRefreshToken", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(session, nil)
+	mockActiveToken(mockSession)
+
+	// act
+	res, err := u.Refresh(context.Background(), login.RefreshToken)
+	assert.NoError(t, err)
+	claims, err := u.ParseAccessToken(context.Background(), res.AccessToken)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, model.RoleAdmin, claims.Role)
+}
+
+func TestParseAccessToken_Revoked(t *testing.T) {
+	// arrange
+	mockSession := new(mocks.SessionRepositoryMock)
+	u := usecase.NewAuthUsecase(mockSession, testKeys)
+	login := issueTestTokens(t, mockSession, u)
+
+	mockSession.On("IsTokenRevoked", mock.Anything, mock.AnythingOfType("string")).Return(true, nil)
+
+	// act
+	claims, err := u.ParseAccessToken(context.Background(), login.AccessToken)
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrTokenRevoked)
+	assert.Nil(t, claims)
+}
+
+func TestParseAccessToken_SiblingTokenRejectedAfterLogout(t *testing.T) {
+	// arrange: a refresh gives a second access token for the same session
+	mockSession := new(mocks.SessionRepositoryMock)
+	u := usecase.NewAuthUsecase(mockSession, testKeys)
+	mockSession.On("CreateSession", mock.Anything, mock.AnythingOfType("*model.Session"), mock.AnythingOfType("string")).Return(nil)
+	login, err := u.IssueTokens(context.Background(), &model.User{ID: 1, Email: "test@example.com"})
+	assert.NoError(t, err)
+
+	sessionID := mockSession.Calls[0].Arguments.Get(1).(*model.Session).ID
+	session := &model.Session{ID: sessionID, UserID: 1, Email: "test@example.com", ExpiresAt: time.Now().Add(time.Hour)}
+	mockSession.On("RotateRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(session, nil)
+	refreshed, err := u.Refresh(context.Background(), login.RefreshToken)
+	assert.NoError(t, err)
+
+	mockSession.On("IsTokenRevoked", mock.Anything, mock.AnythingOfType("string")).Return(false, nil)
+	mockSession.On("IsSessionRevoked", mock.Anything, sessionID).Return(false, nil).Once()
+	claims, err := u.ParseAccessToken(context.Background(), refreshed.AccessToken)
+	assert.NoError(t, err)
+
+	mockSession.On("RevokeSession", mock.Anything, sessionID).Return(nil)
+	mockSession.On("RevokeToken", mock.Anything, claims.JTI, claims.ExpiresAt).Return(nil)
+	assert.NoError(t, u.Logout(context.Background(), claims))
+	mockSession.On("IsSessionRevoked", mock.Anything, sessionID).Return(true, nil)
+
+	// act: the first access token was never revoked by jti
+	sibling, err := u.ParseAccessToken(context.Background(), login.AccessToken)
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrTokenRevoked)
+	assert.Nil(t, sibling)
+}
+
+func TestParseAccessToken_RequiresAccessAudience(t *testing.T) {
+	mockSession := new(mocks.SessionRepositoryMock)
+	u := usecase.NewAuthUsecase(mockSession, testKeys)
+	mockActiveToken(mockSession)
+
+	// correctly signed and shaped like an access token, but meant for something else
+	claims := func(aud ...string) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"user_id": 1,
+			"sid":     "01JAZ3N6W5Q2K8X4T7R9M1B0CD",
+			"jti":     "01JAZ3N6W5Q2K8X4T7R9M1B0CE",
+			"exp":     time.Now().Add(time.Minute).Unix(),
+		}
+		if len(aud) > 0 {
+			c["aud"] = aud
+		}
+		return c
+	}
+
+	for name, c := range map[string]jwt.MapClaims{
+		"no audience":        claims(),
+		"challenge audience": claims("ewallet:login_2fa"),
+		"action audience":    claims("ewallet:account_action"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			token, err := testKeys.Sign(c)
+			assert.NoError(t, err)
+
+			_, err = u.ParseAccessToken(context.Background(), token)
+
+			assert.ErrorIs(t, err, usecase.ErrTokenInvalid)
+		})
+	}
+}
+
+func TestParseAccessToken_Tampered(t *testing.T) {
+	mockSession := new(mocks.SessionRepositoryMock)
+	u := usecase.NewAuthUsecase(mockSession, testKeys)
+	login := issueTestTokens(t, mockSession, u)
+
+	_, err := u.ParseAccessToken(context.Background(), login.AccessToken+"x")
+
+	assert.ErrorIs(t, err, usecase.ErrTokenInvalid)
+	mockSession.AssertNotCalled(t, "IsTokenRevoked", mock.Anything, mock.Anything)
+}
+
+func TestLogout_RevokesSessionAndToken(t *testing.T) {
+	// arrange
+	mockSession := new(mocks.SessionRepositoryMock)
+	u := usecase.NewAuthUsecase(mockSession, testKeys)
+
+	claims := &model.AccessClaims{UserID: 1, SessionID: "sess", JTI: "jti", ExpiresAt: time.Now().Add(time.Minute)}
+	mockSession.On("RevokeSession", mock.Anything, "sess").Return(nil)
+	mockSession.On("RevokeToken", mock.Anything, "jti", claims.ExpiresAt).Return(nil)
+
+	// act
+	err := u.Logout(context.Background(), claims)
+
+	// assert
+	assert.NoError(t, err)
+	mockSession.AssertExpectations(t)
+}
+
+func TestParseAccessToken_SurvivesKeyRotation(t *testing.T) {
+	// arrange: a token issued before the rotation
+	mockSession := new(mocks.SessionRepositoryMock)
+	oldKey, _ := jwtkeys.GenerateEd25519("2026-09")
+	before, _ := jwtkeys.New(oldKey)
+	login := issueTestTokens(t, mockSession, usecase.NewAuthUsecase(mockSession, before))
+
+	// the new key signs, the old one only verifies
+	newKey, _ := jwtkeys.GenerateEd25519("2026-10")
+	after, _ := jwtkeys.New(newKey, oldKey)
+	u := usecase.NewAuthUsecase(mockSession, after)
+	mockActiveToken(mockSession)
+
+	// act
+	claims, err := u.ParseAccessToken(context.Background(), login.AccessToken)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, claims.UserID)
+}
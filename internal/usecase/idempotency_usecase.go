@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"time"
+)
+
+// IdempotencyKeyTTL is how long a key is remembered; after that it may be used again.
+const IdempotencyKeyTTL = 24 * time.Hour
+
+var (
+	ErrIdempotencyKeyReused  = errors.New("Idempotency-Key sudah dipakai untuk request yang berbeda")
+	ErrIdempotencyInProgress = errors.New("Request dengan Idempotency-Key ini masih diproses")
+)
+
+type IdempotencyUsecase struct {
+	IdempotencyRepo repository.IdempotencyRepository
+}
+
+func NewIdempotencyUsecase(repo repository.IdempotencyRepository) *IdempotencyUsecase {
+	return &IdempotencyUsecase{IdempotencyRepo: repo}
+}
+
+// Begin claims the key for a new request. If the key was already used with the same
+// fingerprint, the stored record is returned and Completed() tells the caller to replay it.
+func (u *IdempotencyUsecase) Begin(ctx context.Context, userID int, endpoint, key string, body []byte) (*model.IdempotencyRecord, error) {
+	record := &model.IdempotencyRecord{
+		UserID:      userID,
+		Key:         key,
+		Endpoint:    endpoint,
+		RequestHash: fingerprint(endpoint, body),
+	}
+
+	reserved, err := u.IdempotencyRepo.Reserve(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return record, nil
+	}
+
+	existing, err := u.IdempotencyRepo.Find(ctx, userID, endpoint, key)
+	if err != nil {
+		return nil, err
+	}
+	if existing.RequestHash != record.RequestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if !existing.Completed() {
+		return nil, ErrIdempotencyInProgress
+	}
+	return existing, nil
+}
+
+// Finish stores the response for later replays. Server errors release the key instead,
+// since nothing was committed and the client should be able to retry (e.g. 503 LIMIT_USAGE_CHANGED).
+func (u *IdempotencyUsecase) Finish(ctx context.Context, record *model.IdempotencyRecord, status int, body []byte) error {
+	if status >= 500 {
+		return u.IdempotencyRepo.Release(ctx, record.ID)
+	}
+	return u.IdempotencyRepo.Complete(ctx, record.ID, status, body)
+}
+
+// PurgeExpired forgets the keys older than IdempotencyKeyTTL.
+func (u *IdempotencyUsecase) PurgeExpired(ctx context.Context) (int64, error) {
+	return u.IdempotencyRepo.PurgeOlderThan(ctx, time.Now().Add(-IdempotencyKeyTTL))
+}
+
+func fingerprint(endpoint string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(endpoint))
+	h.Write([]byte{0})
+	h.Write(canonicalJSON(body))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// canonicalJSON re-encodes a JSON body with sorted keys and no insignificant whitespace, so a
+// retry that serialises the same request differently still matches. Numbers are kept as written.
+// A body that is not JSON (or is empty) is hashed as it is.
+func canonicalJSON(body []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return body
+	}
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return canonical
+}
@@ -0,0 +1,131 @@
+package usecase_test
+
+import (
+	"context"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository/mocks"
+	"ewallet-service/internal/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdempotencyBegin_NewKey(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.IdempotencyRepositoryMock)
+	u := usecase.NewIdempotencyUsecase(mockRepo)
+
+	mockRepo.On("Reserve", mock.Anything, mock.AnythingOfType("*model.IdempotencyRecord")).Return(true, nil)
+
+	// act
+	rec, err := u.Begin(context.Background(), 1, "/api/v1/topup", "key-1", []byte(`{"amount":"50000"}`))
+
+	// assert
+	assert.NoError(t, err)
+	assert.False(t, rec.Completed())
+	assert.Len(t, rec.RequestHash, 64)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIdempotencyBegin_ReplaySameBody(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.IdempotencyRepositoryMock)
+	u := usecase.NewIdempotencyUsecase(mockRepo)
+
+	body := []byte(`{"amount":"50000"}`)
+	existing := &model.IdempotencyRecord{ID: 7, ResponseStatus: 200, ResponseBody: []byte(`{"status":"success"}`)}
+	mockRepo.On("Reserve", mock.Anything, mock.AnythingOfType("*model.IdempotencyRecord")).
+		Run(func(args mock.Arguments) {
+			// the stored record was created by an identical earlier request
+			existing.RequestHash = args.Get(1).(*model.IdempotencyRecord).RequestHash
+		}).
+		Return(false, nil)
+	mockRepo.On("Find", mock.Anything, 1, "/api/v1/topup", "key-1").Return(existing, nil)
+
+	// act
+	rec, err := u.Begin(context.Background(), 1, "/api/v1/topup", "key-1", body)
+
+	// assert
+	assert.NoError(t, err)
+	assert.True(t, rec.Completed())
+	assert.Equal(t, 200, rec.ResponseStatus)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIdempotencyBegin_DifferentBody(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.IdempotencyRepositoryMock)
+	u := usecase.NewIdempotencyUsecase(mockRepo)
+
+	mockRepo.On("Reserve", mock.Anything, mock.AnythingOfType("*model.IdempotencyRecord")).Return(false, nil)
+	mockRepo.On("Find", mock.Anything, 1, "/api/v1/transfer", "key-1").
+		Return(&model.IdempotencyRecord{ID: 7, RequestHash: "other", ResponseStatus: 200}, nil)
+
+	// act
+	rec, err := u.Begin(context.Background(), 1, "/api/v1/transfer", "key-1", []byte(`{"amount":"1"}`))
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrIdempotencyKeyReused)
+	assert.Nil(t, rec)
+}
+
+func TestIdempotencyBegin_SameJSONDifferentFormatting(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.IdempotencyRepositoryMock)
+	u := usecase.NewIdempotencyUsecase(mockRepo)
+
+	var hashes []string
+	mockRepo.On("Reserve", mock.Anything, mock.AnythingOfType("*model.IdempotencyRecord")).
+		Run(func(args mock.Arguments) {
+			hashes = append(hashes, args.Get(1).(*model.IdempotencyRecord).RequestHash)
+		}).
+		Return(true, nil)
+
+	// act
+	_, err1 := u.Begin(context.Background(), 1, "/api/v1/transfer", "key-1", []byte(`{"to_wallet_number":"1001","amount":"50000"}`))
+	_, err2 := u.Begin(context.Background(), 1, "/api/v1/transfer", "key-1", []byte("{\n  \"amount\": \"50000\",\n  \"to_wallet_number\": \"1001\"\n}"))
+	_, err3 := u.Begin(context.Background(), 1, "/api/v1/transfer", "key-1", []byte(`{"to_wallet_number":"1001","amount":"50001"}`))
+
+	// assert
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.NoError(t, err3)
+	assert.Equal(t, hashes[0], hashes[1])
+	assert.NotEqual(t, hashes[0], hashes[2])
+}
+
+func TestIdempotencyFinish_ServerErrorReleasesKey(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.IdempotencyRepositoryMock)
+	u := usecase.NewIdempotencyUsecase(mockRepo)
+
+	mockRepo.On("Release", mock.Anything, 7).Return(nil)
+
+	// act
+	err := u.Finish(context.Background(), &model.IdempotencyRecord{ID: 7}, 500, []byte(`{}`))
+
+	// assert
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIdempotencyPurgeExpired(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.IdempotencyRepositoryMock)
+	u := usecase.NewIdempotencyUsecase(mockRepo)
+
+	mockRepo.On("PurgeOlderThan", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before) >= usecase.IdempotencyKeyTTL && time.Since(before) < usecase.IdempotencyKeyTTL+time.Minute
+	})).Return(int64(3), nil)
+
+	// act
+	purged, err := u.PurgeExpired(context.Background())
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// failures are forgotten after an hour without a new one; this must outlast the longest
+// lock, otherwise the backoff would start from the bottom again after every lock
+const attemptWindow = time.Hour
+
+var ErrLoginLocked = errors.New("Terlalu banyak percobaan login gagal, coba lagi nanti")
+
+// LoginLockedError is returned while an account or IP is locked. errors.Is(err, ErrLoginLocked) holds.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("Terlalu banyak percobaan login gagal, coba lagi dalam %s", e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginLockedError) Is(target error) bool {
+	return target == ErrLoginLocked
+}
+
+// attemptPolicy: the first freeAttempts failures only count, every failure after that
+// locks the key for baseLock, doubling each time up to maxLock.
+type attemptPolicy struct {
+	prefix       string
+	freeAttempts int
+	baseLock     time.Duration
+	maxLock      time.Duration
+}
+
+var (
+	accountPolicy = attemptPolicy{prefix: "account:", freeAttempts: 5, baseLock: 30 * time.Second, maxLock: 30 * time.Minute}
+	// one IP (an office, a mobile carrier NAT) may serve many users, so it gets more room
+	ipPolicy = attemptPolicy{prefix: "ip:", freeAttempts: 20, baseLock: time.Minute, maxLock: time.Hour}
+)
+
+func (p attemptPolicy) lockFor(failures int) time.Duration {
+	if failures < p.freeAttempts {
+		return 0
+	}
+	lock := p.baseLock
+	for i := p.freeAttempts; i < failures && lock < p.maxLock; i++ {
+		lock *= 2
+	}
+	if lock > p.maxLock {
+		lock = p.maxLock
+	}
+	return lock
+}
+
+// LoginAttemptUsecase throttles password and OTP guessing per account and per client IP.
+type LoginAttemptUsecase struct {
+	AttemptRepo repository.LoginAttemptRepository
+	AuditRepo   repository.AuditRepository
+}
+
+func NewLoginAttemptUsecase(attemptRepo repository.LoginAttemptRepository, auditRepo repository.AuditRepository) *LoginAttemptUsecase {
+	return &LoginAttemptUsecase{AttemptRepo: attemptRepo, AuditRepo: auditRepo}
+}
+
+// Check returns a *LoginLockedError when the account or the IP is locked.
+func (u *LoginAttemptUsecase) Check(ctx context.Context, email, ip string) error {
+	now := time.Now()
+	for _, key := range attemptKeys(email, ip) {
+		a, err := u.AttemptRepo.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if a.Locked(now) {
+			return &LoginLockedError{RetryAfter: a.LockedUntil.Sub(now)}
+		}
+	}
+	return nil
+}
+
+// Failure counts a failed attempt for the account and the IP and locks whichever went over its limit.
+func (u *LoginAttemptUsecase) Failure(ctx context.Context, email, ip string) error {
+	policies := []attemptPolicy{accountPolicy, ipPolicy}
+	for i, key := range attemptKeys(email, ip) {
+		a, err := u.AttemptRepo.RecordFailure(ctx, key, attemptWindow)
+		if err != nil {
+			return err
+		}
+
+		lockFor := policies[i].lockFor(a.Failures)
+		if lockFor == 0 {
+			continue
+		}
+		if _, err := u.AttemptRepo.Lock(ctx, key, lockFor); err != nil {
+			return err
+		}
+		u.audit(ctx, &model.AuditEntry{
+			Event:   model.AuditLoginLocked,
+			Actor:   "system",
+			Subject: key,
+			Detail:  fmt.Sprintf("%d percobaan gagal, dikunci %s", a.Failures, lockFor),
+			IP:      ip,
+		})
+	}
+	return nil
+}
+
+// Success clears the account counter. The IP counter is left alone, otherwise an attacker
+// could reset it by logging into their own account between guesses.
+func (u *LoginAttemptUsecase) Success(ctx context.Context, email string) error {
+	return u.AttemptRepo.Reset(ctx, accountPolicy.prefix+normalizeEmail(email))
+}
+
+// Unlock lifts the lock and clears the counter of an account before the lock expires.
+func (u *LoginAttemptUsecase) Unlock(ctx context.Context, email, actor string) error {
+	key := accountPolicy.prefix + normalizeEmail(email)
+	if err := u.AttemptRepo.Reset(ctx, key); err != nil {
+		return err
+	}
+	u.audit(ctx, &model.AuditEntry{
+		Event:   model.AuditLoginUnlocked,
+		Actor:   actor,
+		Subject: key,
+		Detail:  "Kunci login dibuka manual",
+	})
+	return nil
+}
+
+// PurgeExpired deletes the counters that have been forgotten anyway: no failure within
+// attemptWindow and no lock in force.
+func (u *LoginAttemptUsecase) PurgeExpired(ctx context.Context) (int64, error) {
+	return u.AttemptRepo.Purge(ctx, attemptWindow)
+}
+
+// the audit trail must not turn a rejected login into a 500, so failures are only logged
+func (u *LoginAttemptUsecase) audit(ctx context.Context, entry *model.AuditEntry) {
+	if err := u.AuditRepo.Record(ctx, entry); err != nil {
+		log.Printf("Gagal mencatat audit %s untuk %s: %v", entry.Event, entry.Subject, err)
+	}
+}
+
+func attemptKeys(email, ip string) []string {
+	return []string{accountPolicy.prefix + normalizeEmail(email), ipPolicy.prefix + ip}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,152 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"ewallet-service/internal/repository/mocks"
+	"ewallet-service/internal/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLoginAttempt_LocksAccountAfterFiveFailures(t *testing.T) {
+	// arrange
+	mockAudit := new(mocks.AuditRepositoryMock)
+	u := usecase.NewLoginAttemptUsecase(repository.NewMemoryLoginAttemptRepository(), mockAudit)
+
+	mockAudit.On("Record", mock.Anything, mock.MatchedBy(func(e *model.AuditEntry) bool {
+		return e.Event == model.AuditLoginLocked && e.Subject == "account:budi@example.com"
+	})).Return(nil).Once()
+
+	// act
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, u.Failure(context.Background(), "Budi@Example.com", "10.0.0.1"))
+	}
+	errBefore := u.Check(context.Background(), "budi@example.com", "10.0.0.1")
+
+	assert.NoError(t, u.Failure(context.Background(), "budi@example.com", "10.0.0.1"))
+	errAfter := u.Check(context.Background(), "budi@example.com", "10.0.0.2")
+
+	// assert
+	assert.NoError(t, errBefore)
+	assert.ErrorIs(t, errAfter, usecase.ErrLoginLocked)
+
+	var locked *usecase.LoginLockedError
+	assert.True(t, errors.As(errAfter, &locked))
+	assert.InDelta(t, 30*time.Second, locked.RetryAfter, float64(time.Second))
+	mockAudit.AssertExpectations(t)
+}
+
+func TestLoginAttempt_BackoffDoubles(t *testing.T) {
+	// arrange
+	repo := repository.NewMemoryLoginAttemptRepository()
+	mockAudit := new(mocks.AuditRepositoryMock)
+	u := usecase.NewLoginAttemptUsecase(repo, mockAudit)
+	mockAudit.On("Record", mock.Anything, mock.Anything).Return(nil)
+
+	// act: the 7th failure (two over the limit)
+	for i := 0; i < 7; i++ {
+		u.Failure(context.Background(), "budi@example.com", "10.0.0.1")
+	}
+	err := u.Check(context.Background(), "budi@example.com", "")
+
+	// assert
+	var locked *usecase.LoginLockedError
+	assert.True(t, errors.As(err, &locked))
+	assert.InDelta(t, 2*time.Minute, locked.RetryAfter, float64(time.Second))
+}
+
+func TestLoginAttempt_LocksIPAcrossAccounts(t *testing.T) {
+	// arrange
+	mockAudit := new(mocks.AuditRepositoryMock)
+	u := usecase.NewLoginAttemptUsecase(repository.NewMemoryLoginAttemptRepository(), mockAudit)
+	mockAudit.On("Record", mock.Anything, mock.Anything).Return(nil)
+
+	// act: credential stuffing, one try per account from the same IP
+	for i := 0; i < 20; i++ {
+		u.Failure(context.Background(), "user"+string(rune('a'+i))+"@example.com", "10.0.0.9")
+	}
+
+	// assert
+	assert.ErrorIs(t, u.Check(context.Background(), "fresh@example.com", "10.0.0.9"), usecase.ErrLoginLocked)
+	assert.NoError(t, u.Check(context.Background(), "fresh@example.com", "10.0.0.10"))
+}
+
+func TestLoginAttempt_UnlockIsAudited(t *testing.T) {
+	// arrange
+	mockAudit := new(mocks.AuditRepositoryMock)
+	u := usecase.NewLoginAttemptUsecase(repository.NewMemoryLoginAttemptRepository(), mockAudit)
+	mockAudit.On("Record", mock.Anything, mock.MatchedBy(func(e *model.AuditEntry) bool {
+		return e.Event == model.AuditLoginLocked
+	})).Return(nil)
+	mockAudit.On("Record", mock.Anything, mock.MatchedBy(func(e *model.AuditEntry) bool {
+		return e.Event == model.AuditLoginUnlocked && e.Actor == "support:andi"
+	})).Return(nil).Once()
+
+	for i := 0; i < 5; i++ {
+		u.Failure(context.Background(), "budi@example.com", "10.0.0.1")
+	}
+
+	// act
+	err := u.Unlock(context.Background(), "budi@example.com", "support:andi")
+
+	// assert
+	assert.NoError(t, err)
+	assert.NoError(t, u.Check(context.Background(), "budi@example.com", "10.0.0.2"))
+	mockAudit.AssertExpectations(t)
+}
+
+func TestLogin_LockedAccountSkipsPasswordCheck(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.UserRepositoryMock)
+	mockAudit := new(mocks.AuditRepositoryMock)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys))
+	u.Attempts = usecase.NewLoginAttemptUsecase(repository.NewMemoryLoginAttemptRepository(), mockAudit)
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	mockRepo.On("FindByEmail", mock.Anything, "budi@example.com").Return(&model.User{ID: 1, Email: "budi@example.com", Password: string(hashedPassword)}, nil)
+	mockAudit.On("Record", mock.Anything, mock.Anything).Return(nil)
+
+	wrong := model.LoginRequest{Email: "budi@example.com", Password: "salah", ClientIP: "10.0.0.1"}
+	for i := 0; i < 5; i++ {
+		u.Login(context.Background(), wrong)
+	}
+
+	// act: even the right password is refused while locked
+	_, err := u.Login(context.Background(), model.LoginRequest{Email: "budi@example.com", Password: "password123", ClientIP: "10.0.0.1"})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrLoginLocked)
+	mockRepo.AssertNumberOfCalls(t, "FindByEmail", 5)
+}
+
+func TestLoginAttempt_PurgeKeepsCurrentWindows(t *testing.T) {
+	// arrange
+	repo := repository.NewMemoryLoginAttemptRepository()
+	u := usecase.NewLoginAttemptUsecase(repo, new(mocks.AuditRepositoryMock))
+	assert.NoError(t, u.Failure(context.Background(), "budi@example.com", "10.0.0.1"))
+
+	// act
+	purged, err := u.PurgeExpired(context.Background())
+
+	// assert
+	assert.NoError(t, err)
+	assert.Zero(t, purged)
+	a, _ := repo.Get(context.Background(), "account:budi@example.com")
+	assert.Equal(t, 1, a.Failures)
+
+	// a counter past its window goes, a locked one stays
+	_, _ = repo.RecordFailure(context.Background(), "ip:10.0.0.2", time.Hour)
+	_, _ = repo.Lock(context.Background(), "ip:10.0.0.2", time.Hour)
+	purged, err = repo.Purge(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+	a, _ = repo.Get(context.Background(), "ip:10.0.0.2")
+	assert.Equal(t, 1, a.Failures)
+}
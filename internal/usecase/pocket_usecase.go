@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"strings"
+)
+
+var (
+	ErrPocketNotFound    = errors.New("Pocket tidak ditemukan")
+	ErrInvalidPocketName = errors.New("Nama pocket tidak boleh kosong")
+)
+
+// PocketUsecase manages the user's pockets: named wallets next to the primary one, each with its
+// own number and balance. Money moves between them instantly, without fee or limits, as it never
+// leaves the user. A move still needs the PIN, like a transfer between own pockets does.
+type PocketUsecase struct {
+	PocketRepo      repository.PocketRepository
+	TransactionRepo repository.TransactionRepository
+	PIN             PINVerifier
+}
+
+func NewPocketUsecase(pocketRepo repository.PocketRepository, trxRepo repository.TransactionRepository, pin PINVerifier) *PocketUsecase {
+	return &PocketUsecase{PocketRepo: pocketRepo, TransactionRepo: trxRepo, PIN: pin}
+}
+
+func (u *PocketUsecase) ListPockets(ctx context.Context, userID int) ([]model.Wallet, error) {
+	return u.PocketRepo.ListPockets(ctx, userID)
+}
+
+func (u *PocketUsecase) CreatePocket(ctx context.Context, userID int, req model.CreatePocketRequest) (model.Wallet, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return model.Wallet{}, ErrInvalidPocketName
+	}
+	return u.PocketRepo.CreatePocket(ctx, userID, name)
+}
+
+// MovePocketFunds is a transfer whose recipient must be another pocket of the same user.
+// Pockets never change owner, so checking it before the transfer is enough.
+func (u *PocketUsecase) MovePocketFunds(ctx context.Context, userID int, req model.PocketMoveRequest) (model.TransferResponse, error) {
+	if err := u.PIN.VerifyPIN(ctx, userID, req.PIN); err != nil {
+		return model.TransferResponse{}, err
+	}
+
+	target, err := u.TransactionRepo.FindWalletByNumber(ctx, req.ToWalletNumber)
+	if errors.Is(err, repository.ErrRecipientNotFound) || (err == nil && target.UserID != userID) {
+		return model.TransferResponse{}, ErrPocketNotFound
+	}
+	if err != nil {
+		return model.TransferResponse{}, err
+	}
+
+	transfer := model.TransferRequest{
+		SourceWalletNumber: req.FromWalletNumber,
+		TargetWalletNumber: req.ToWalletNumber,
+		Amount:             req.Amount,
+		Description:        req.Description,
+		PIN:                req.PIN,
+	}
+	return u.TransactionRepo.Transfer(ctx, userID, transfer, model.NewMoney(0), model.UsageCheck{})
+}
@@ -0,0 +1,103 @@
+package usecase_test
+
+import (
+	"context"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"ewallet-service/internal/repository/mocks"
+	"ewallet-service/internal/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreatePocket_TrimsName(t *testing.T) {
+	// arrange
+	mockPockets := new(mocks.PocketRepositoryMock)
+	u := usecase.NewPocketUsecase(mockPockets, new(mocks.TransactionRepositoryMock), verifierStub{})
+	pocket := model.Wallet{ID: 7, UserID: 1, Name: "Tabungan", WalletNumber: "100123456780"}
+	mockPockets.On("CreatePocket", mock.Anything, 1, "Tabungan").Return(pocket, nil)
+
+	// act
+	res, err := u.CreatePocket(context.Background(), 1, model.CreatePocketRequest{Name: "  Tabungan "})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, pocket, res)
+	mockPockets.AssertExpectations(t)
+}
+
+func TestCreatePocket_BlankName(t *testing.T) {
+	// arrange
+	mockPockets := new(mocks.PocketRepositoryMock)
+	u := usecase.NewPocketUsecase(mockPockets, new(mocks.TransactionRepositoryMock), verifierStub{})
+
+	// act
+	_, err := u.CreatePocket(context.Background(), 1, model.CreatePocketRequest{Name: "   "})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrInvalidPocketName)
+	mockPockets.AssertNotCalled(t, "CreatePocket", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMovePocketFunds_Success(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := usecase.NewPocketUsecase(new(mocks.PocketRepositoryMock), mockRepo, verifierStub{})
+	req := model.PocketMoveRequest{FromWalletNumber: "100123456780", ToWalletNumber: "100999", Amount: model.NewMoney(75000), PIN: "123456"}
+
+	mockTargetWallet(mockRepo, "100999", 1)
+	expected := model.TransferRequest{SourceWalletNumber: "100123456780", TargetWalletNumber: "100999", Amount: model.NewMoney(75000), PIN: "123456"}
+	mockRepo.On("Transfer", mock.Anything, 1, expected, model.NewMoney(0), mock.Anything).Return(model.TransferResponse{ID: "TRX-1", Amount: model.NewMoney(75000)}, nil)
+
+	// act
+	res, err := u.MovePocketFunds(context.Background(), 1, req)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "TRX-1", res.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMovePocketFunds_NotOwnPocket(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(*mocks.TransactionRepositoryMock)
+	}{
+		{"someone else's wallet", func(m *mocks.TransactionRepositoryMock) { mockTargetWallet(m, "100999", 2) }},
+		{"unknown wallet", func(m *mocks.TransactionRepositoryMock) {
+			m.On("FindWalletByNumber", mock.Anything, "100999").Return(nil, repository.ErrRecipientNotFound)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			mockRepo := new(mocks.TransactionRepositoryMock)
+			u := usecase.NewPocketUsecase(new(mocks.PocketRepositoryMock), mockRepo, verifierStub{})
+			tt.setup(mockRepo)
+
+			// act
+			_, err := u.MovePocketFunds(context.Background(), 1, model.PocketMoveRequest{ToWalletNumber: "100999", Amount: model.NewMoney(1000)})
+
+			// assert
+			assert.ErrorIs(t, err, usecase.ErrPocketNotFound)
+			mockRepo.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestMovePocketFunds_WrongPIN(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := usecase.NewPocketUsecase(new(mocks.PocketRepositoryMock), mockRepo, verifierStub{err: usecase.ErrPINInvalid})
+
+	// act
+	_, err := u.MovePocketFunds(context.Background(), 1, model.PocketMoveRequest{ToWalletNumber: "100999", Amount: model.NewMoney(1000), PIN: "000000"})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrPINInvalid)
+	mockRepo.AssertNotCalled(t, "FindWalletByNumber", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
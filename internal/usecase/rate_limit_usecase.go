@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ewallet-service/internal/repository"
+	"fmt"
+	"time"
+)
+
+var ErrRateLimited = errors.New("Terlalu banyak permintaan, coba lagi nanti")
+
+// RateLimitedError is returned once a key used up its window. errors.Is(err, ErrRateLimited) holds.
+type RateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("Terlalu banyak permintaan, coba lagi dalam %s", e.RetryAfter.Round(time.Second))
+}
+
+func (e *RateLimitedError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+type RateLimitUsecase struct {
+	RateLimitRepo repository.RateLimitRepository
+}
+
+func NewRateLimitUsecase(repo repository.RateLimitRepository) *RateLimitUsecase {
+	return &RateLimitUsecase{RateLimitRepo: repo}
+}
+
+// Allow counts a request for key and returns a *RateLimitedError when it is the limit+1th
+// within the current window. Windows are fixed (a day window runs from 00:00 UTC).
+func (u *RateLimitUsecase) Allow(ctx context.Context, key string, limit int, window time.Duration) error {
+	now := time.Now()
+	windowStart := now.UTC().Truncate(window)
+
+	hits, err := u.RateLimitRepo.Hit(ctx, fmt.Sprintf("%s:%s", key, window), windowStart)
+	if err != nil {
+		return err
+	}
+	if hits > limit {
+		return &RateLimitedError{RetryAfter: windowStart.Add(window).Sub(now)}
+	}
+	return nil
+}
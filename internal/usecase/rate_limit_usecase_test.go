@@ -0,0 +1,47 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"ewallet-service/internal/repository"
+	"ewallet-service/internal/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit_RefusesOverLimit(t *testing.T) {
+	// arrange
+	u := usecase.NewRateLimitUsecase(repository.NewMemoryRateLimitRepository())
+
+	// act
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, u.Allow(context.Background(), "inquiry:user:1", 10, time.Hour))
+	}
+	err := u.Allow(context.Background(), "inquiry:user:1", 10, time.Hour)
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrRateLimited)
+
+	var limited *usecase.RateLimitedError
+	assert.True(t, errors.As(err, &limited))
+	assert.Greater(t, limited.RetryAfter, time.Duration(0))
+	assert.LessOrEqual(t, limited.RetryAfter, time.Hour)
+}
+
+func TestRateLimit_KeysAndWindowsAreIndependent(t *testing.T) {
+	// arrange
+	u := usecase.NewRateLimitUsecase(repository.NewMemoryRateLimitRepository())
+	for i := 0; i < 2; i++ {
+		u.Allow(context.Background(), "inquiry:user:1", 1, time.Hour)
+	}
+
+	// act
+	otherUser := u.Allow(context.Background(), "inquiry:user:2", 1, time.Hour)
+	otherWindow := u.Allow(context.Background(), "inquiry:user:1", 5, 24*time.Hour)
+
+	// assert
+	assert.NoError(t, otherUser)
+	assert.NoError(t, otherWindow)
+}
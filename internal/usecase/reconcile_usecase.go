@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"time"
+)
+
+type ReconcileUsecase struct {
+	ReconcileRepo repository.ReconcileRepository
+}
+
+func NewReconcileUsecase(repo repository.ReconcileRepository) *ReconcileUsecase {
+	return &ReconcileUsecase{ReconcileRepo: repo}
+}
+
+// Run checks every wallet and reports the ones whose history does not add up to their balance.
+// With apply set, an adjustment row is written for each of them.
+func (u *ReconcileUsecase) Run(ctx context.Context, apply bool) (model.ReconcileReport, error) {
+	wallets, err := u.ReconcileRepo.ListWalletReconciliations(ctx)
+	if err != nil {
+		return model.ReconcileReport{}, err
+	}
+
+	report := model.ReconcileReport{
+		GeneratedAt:     time.Now(),
+		WalletsChecked:  len(wallets),
+		TotalDifference: model.NewMoney(0),
+		Applied:         apply,
+		Discrepancies:   []model.WalletReconciliation{},
+	}
+
+	for _, w := range wallets {
+		if w.Matches() {
+			continue
+		}
+
+		if apply {
+			adjusted, err := u.ReconcileRepo.AdjustHistory(ctx, w.WalletID)
+			if err != nil {
+				return report, err
+			}
+			// zero means the history already matched when the wallet was locked
+			w.Adjusted = !adjusted.IsZero()
+		}
+
+		report.TotalDifference = report.TotalDifference.Add(w.Difference)
+		report.Discrepancies = append(report.Discrepancies, w)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,77 @@
+package usecase_test
+
+import (
+	"context"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository/mocks"
+	"ewallet-service/internal/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func reconcileFixture() []model.WalletReconciliation {
+	return []model.WalletReconciliation{
+		{WalletID: 1, WalletNumber: "1001", Balance: model.NewMoney(50000), HistoryBalance: model.NewMoney(50000), LedgerBalance: model.NewMoney(50000), TransactionCount: 2},
+		{WalletID: 2, WalletNumber: "1002", Balance: model.NewMoney(30000), HistoryBalance: model.NewMoney(25000), LedgerBalance: model.NewMoney(30000), Difference: model.NewMoney(5000), TransactionCount: 3},
+	}
+}
+
+func TestReconcile_ReportOnly(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.ReconcileRepositoryMock)
+	u := usecase.NewReconcileUsecase(mockRepo)
+
+	mockRepo.On("ListWalletReconciliations", mock.Anything).Return(reconcileFixture(), nil)
+
+	// act
+	report, err := u.Run(context.Background(), false)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.WalletsChecked)
+	assert.Len(t, report.Discrepancies, 1)
+	assert.Equal(t, "1002", report.Discrepancies[0].WalletNumber)
+	assert.Equal(t, "5000.00", report.TotalDifference.String())
+	assert.False(t, report.Discrepancies[0].Adjusted)
+	mockRepo.AssertNotCalled(t, "AdjustHistory", mock.Anything, mock.Anything)
+}
+
+func TestReconcile_Apply(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.ReconcileRepositoryMock)
+	u := usecase.NewReconcileUsecase(mockRepo)
+
+	mockRepo.On("ListWalletReconciliations", mock.Anything).Return(reconcileFixture(), nil)
+	mockRepo.On("AdjustHistory", mock.Anything, 2).Return(model.NewMoney(5000), nil)
+
+	// act
+	report, err := u.Run(context.Background(), true)
+
+	// assert
+	assert.NoError(t, err)
+	assert.True(t, report.Applied)
+	assert.True(t, report.Discrepancies[0].Adjusted)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReconcile_LedgerMismatchIsReported(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.ReconcileRepositoryMock)
+	u := usecase.NewReconcileUsecase(mockRepo)
+
+	wallets := []model.WalletReconciliation{
+		{WalletID: 3, WalletNumber: "1003", Balance: model.NewMoney(10000), HistoryBalance: model.NewMoney(10000), LedgerBalance: model.NewMoney(9000), TransactionCount: 1},
+	}
+	mockRepo.On("ListWalletReconciliations", mock.Anything).Return(wallets, nil)
+
+	// act
+	report, err := u.Run(context.Background(), false)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, report.Discrepancies, 1)
+	assert.False(t, report.Discrepancies[0].LedgerMatches())
+	assert.True(t, report.TotalDifference.IsZero())
+}
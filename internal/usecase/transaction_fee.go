@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"ewallet-service/internal/model"
+	"fmt"
+	"os"
+)
+
+var ErrFeeExceedsAmount = errors.New("Biaya topup melebihi nominal topup")
+
+// LoadFeeSchedule reads the fee rules from a JSON file shaped like model.FeeSchedule,
+// e.g. {"transfer": {"unverified": {"type": "flat", "flat": "2500"}}}.
+func LoadFeeSchedule(path string) (model.FeeSchedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule model.FeeSchedule
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return schedule, nil
+}
+
+// fee returns the fee of one transaction of kind and the rule it came from, if any.
+func (u *TransactionUsecase) fee(kind string, usage model.LimitUsage, amount model.Money) (model.Money, *model.FeeRule) {
+	rule, ok := u.Fees.Rule(kind, usage.Tier)
+	if !ok {
+		return model.NewMoney(0), nil
+	}
+	return rule.Fee(amount, feeCount(kind, usage)), &rule
+}
+
+// feeCount is how many transactions of kind count against the free quota this month.
+func feeCount(kind string, usage model.LimitUsage) int {
+	if kind == model.FeeKindTopUp {
+		return usage.TopUp.MonthlyCount
+	}
+	return usage.TransferOut.MonthlyCount
+}
+
+// topUpFee is taken from the top-up itself, so it must leave something to credit.
+func (u *TransactionUsecase) topUpFee(usage model.LimitUsage, amount model.Money) (model.Money, error) {
+	fee, _ := u.fee(model.FeeKindTopUp, usage, amount)
+	if !fee.LessThan(amount) {
+		return model.Money{}, ErrFeeExceedsAmount
+	}
+	return fee, nil
+}
+
+func (u *TransactionUsecase) transferFee(usage model.LimitUsage, amount model.Money) model.Money {
+	fee, _ := u.fee(model.FeeKindTransfer, usage, amount)
+	return fee
+}
+
+// QuoteFee shows the fee a top-up or transfer of req.Amount would cost right now. Nothing is held:
+// POST /topup and POST /transfer price themselves when they run, so a free transfer shown here may be
+// used up meanwhile. Only a transfer quote (QuoteTransfer) caps the fee that is charged.
+func (u *TransactionUsecase) QuoteFee(ctx context.Context, userID int, req model.FeeQuoteRequest) (model.FeeQuote, error) {
+	usage, _, err := u.usage(ctx, userID)
+	if err != nil {
+		return model.FeeQuote{}, err
+	}
+
+	fee, rule := u.fee(req.Type, usage, req.Amount)
+	quote := model.FeeQuote{Type: req.Type, Amount: req.Amount, Fee: fee}
+	if req.Type == model.FeeKindTopUp {
+		if !fee.LessThan(req.Amount) {
+			return model.FeeQuote{}, ErrFeeExceedsAmount
+		}
+		quote.Total = req.Amount.Sub(fee)
+	} else {
+		quote.Total = req.Amount.Add(fee)
+	}
+
+	if rule != nil && rule.FreePerMonth > 0 {
+		left := rule.FreePerMonth - feeCount(req.Type, usage)
+		if left < 0 {
+			left = 0
+		}
+		quote.FreeRemaining = &left
+	}
+	return quote, nil
+}
@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"context"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository/mocks"
+	"ewallet-service/internal/usecase"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testFees() model.FeeSchedule {
+	return model.FeeSchedule{
+		model.FeeKindTopUp: {
+			model.TierUnverified: {Type: model.FeeFlat, Flat: model.NewMoney(1000)},
+		},
+		model.FeeKindTransfer: {
+			model.TierUnverified: {Type: model.FeeFlat, Flat: model.NewMoney(2500)},
+			model.TierVerified:   {Type: model.FeeFlat, Flat: model.NewMoney(2500), FreePerMonth: 3},
+		},
+	}
+}
+
+func newFeeUsecase(repo *mocks.TransactionRepositoryMock) *usecase.TransactionUsecase {
+	u := usecase.NewTransactionUsecase(repo, verifierStub{})
+	u.Fees = testFees()
+	return u
+}
+
+func TestTransfer_ChargesFee(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newFeeUsecase(mockRepo)
+	req := model.TransferRequest{TargetWalletNumber: "100999", Amount: model.NewMoney(50000), PIN: "123456"}
+
+	usage := model.LimitUsage{Tier: model.TierVerified, TransferOut: model.Usage{MonthlyCount: 3}}
+	mockTargetWallet(mockRepo, "100999", 2)
+	mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).Return(usage, nil)
+	mockRepo.On("Transfer", mock.Anything, 1, req, model.NewMoney(2500), mock.Anything).Return(model.TransferResponse{ID: "TRX-1", Fee: model.NewMoney(2500)}, nil)
+
+	// act
+	res, err := u.Transfer(context.Background(), 1, req)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, model.NewMoney(2500), res.Fee)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransfer_FreeQuota(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newFeeUsecase(mockRepo)
+	req := model.TransferRequest{TargetWalletNumber: "100999", Amount: model.NewMoney(50000), PIN: "123456"}
+
+	usage := model.LimitUsage{Tier: model.TierVerified, TransferOut: model.Usage{MonthlyCount: 2}}
+	mockTargetWallet(mockRepo, "100999", 2)
+	mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).Return(usage, nil)
+	mockRepo.On("Transfer", mock.Anything, 1, req, model.NewMoney(0), mock.Anything).Return(model.TransferResponse{ID: "TRX-1"}, nil)
+
+	// act
+	_, err := u.Transfer(context.Background(), 1, req)
+
+	// assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTopUp_FeeMustLeaveSomethingToCredit(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newFeeUsecase(mockRepo)
+	mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).Return(model.LimitUsage{Tier: model.TierUnverified}, nil)
+
+	// act
+	_, err := u.TopUp(context.Background(), 1, model.TopUpRequest{Amount: model.NewMoney(1000)})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrFeeExceedsAmount)
+	mockRepo.AssertNotCalled(t, "CreateTopUp", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestQuoteFee(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newFeeUsecase(mockRepo)
+	usage := model.LimitUsage{Tier: model.TierVerified, TransferOut: model.Usage{MonthlyCount: 1}}
+	mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).Return(usage, nil)
+
+	// act
+	transfer, err := u.QuoteFee(context.Background(), 1, model.FeeQuoteRequest{Type: model.FeeKindTransfer, Amount: model.NewMoney(50000)})
+	assert.NoError(t, err)
+	topUp, err := u.QuoteFee(context.Background(), 1, model.FeeQuoteRequest{Type: model.FeeKindTopUp, Amount: model.NewMoney(50000)})
+	assert.NoError(t, err)
+
+	// assert
+	assert.Equal(t, model.NewMoney(0), transfer.Fee)
+	assert.Equal(t, model.NewMoney(50000), transfer.Total)
+	if assert.NotNil(t, transfer.FreeRemaining) {
+		assert.Equal(t, 2, *transfer.FreeRemaining)
+	}
+
+	// verified users fall back to the unverified top-up rule
+	assert.Equal(t, model.NewMoney(1000), topUp.Fee)
+	assert.Equal(t, model.NewMoney(49000), topUp.Total)
+	assert.Nil(t, topUp.FreeRemaining)
+}
+
+func TestLoadFeeSchedule(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	schedule, err := usecase.LoadFeeSchedule(write("ok.json", `{
+		"transfer": {"unverified": {"type": "percentage", "percent_bps": 50, "min": "1000", "max": "10000"}}
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(50), schedule[model.FeeKindTransfer][model.TierUnverified].PercentBps)
+
+	_, err = usecase.LoadFeeSchedule(write("kind.json", `{"withdraw": {"unverified": {"type": "flat"}}}`))
+	assert.ErrorIs(t, err, model.ErrInvalidFeeRule)
+
+	_, err = usecase.LoadFeeSchedule(write("type.json", `{"topup": {"verified": {"type": "cashback"}}}`))
+	assert.ErrorIs(t, err, model.ErrInvalidFeeRule)
+}
+
+func TestTransfer_OwnPocketIsFreeAndUnlimited(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newFeeUsecase(mockRepo)
+	u.Limits = model.DefaultLimitTiers()
+	req := model.TransferRequest{TargetWalletNumber: "100999", Amount: model.NewMoney(50000000), PIN: "123456"}
+
+	mockTargetWallet(mockRepo, "100999", 1)
+	mockRepo.On("Transfer", mock.Anything, 1, req, model.NewMoney(0), mock.Anything).Return(model.TransferResponse{ID: "TRX-1"}, nil)
+
+	// act
+	_, err := u.Transfer(context.Background(), 1, req)
+
+	// assert
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "GetLimitUsage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"ewallet-service/internal/model"
+	"fmt"
+	"os"
+	"time"
+)
+
+var (
+	ErrPerTransactionLimit = errors.New("Nominal melebihi batas per transaksi")
+	ErrDailyLimit          = errors.New("Melebihi limit harian")
+	ErrMonthlyLimit        = errors.New("Melebihi limit bulanan")
+	// the recipient's limits and usage are not the sender's business, so this one carries no amounts
+	ErrRecipientLimit = errors.New("Wallet tujuan tidak dapat menerima dana sebesar ini saat ini")
+)
+
+// LimitExceededError tells how much is still allowed. errors.Is(err, Err) holds.
+type LimitExceededError struct {
+	Err       error
+	Remaining model.Money
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s, sisa limit %s", e.Err, e.Remaining)
+}
+
+func (e *LimitExceededError) Unwrap() error {
+	return e.Err
+}
+
+// LoadLimitTiers reads the limit tiers from a JSON file shaped like
+// {"verified": {"topup": {"per_transaction": "10000000", "daily": ..., "monthly": ...}, ...}, ...}.
+// Every tier must be present; an amount left out means no cap.
+func LoadLimitTiers(path string) (map[string]model.LimitTier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tiers map[string]model.LimitTier
+	if err := json.Unmarshal(data, &tiers); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, tier := range model.LimitTiers {
+		if _, ok := tiers[tier]; !ok {
+			return nil, fmt.Errorf("%s: tier %q tidak ada", path, tier)
+		}
+	}
+	return tiers, nil
+}
+
+// limitWindows are the starts of the current day and month. They are in UTC, like statement periods.
+func limitWindows(now time.Time) (dayStart, monthStart time.Time) {
+	now = now.UTC()
+	dayStart = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return dayStart, monthStart
+}
+
+// checkLimit returns a *LimitExceededError when amount does not fit in limit given what was already used.
+func checkLimit(limit model.Limit, used model.Usage, amount model.Money) error {
+	if !limit.PerTransaction.IsZero() && amount.Cmp(limit.PerTransaction) > 0 {
+		return &LimitExceededError{Err: ErrPerTransactionLimit, Remaining: limit.PerTransaction}
+	}
+	if remaining := remainingOf(limit.Daily, used.Daily); remaining != nil && amount.Cmp(*remaining) > 0 {
+		return &LimitExceededError{Err: ErrDailyLimit, Remaining: *remaining}
+	}
+	if remaining := remainingOf(limit.Monthly, used.Monthly); remaining != nil && amount.Cmp(*remaining) > 0 {
+		return &LimitExceededError{Err: ErrMonthlyLimit, Remaining: *remaining}
+	}
+	return nil
+}
+
+// remainingOf is nil for a limit without cap and never negative otherwise.
+func remainingOf(limit, used model.Money) *model.Money {
+	if limit.IsZero() {
+		return nil
+	}
+	remaining := limit.Sub(used)
+	if remaining.IsNegative() {
+		remaining = model.NewMoney(0)
+	}
+	return &remaining
+}
+
+// tierOf falls back to the unverified tier, so an unknown tier never means "no limits".
+func (u *TransactionUsecase) tierOf(usage model.LimitUsage) model.LimitTier {
+	if tier, ok := u.Limits[usage.Tier]; ok {
+		return tier
+	}
+	return u.Limits[model.TierUnverified]
+}
+
+// usage is what the user moved this day and month; it is only read when limits or fees need it.
+// The returned check goes along to the repository, which locks the user and refuses the transaction
+// when the usage moved meanwhile, so concurrent requests cannot all pass against the same usage.
+func (u *TransactionUsecase) usage(ctx context.Context, userID int) (model.LimitUsage, model.UsageCheck, error) {
+	var check model.UsageCheck
+	check.DayStart, check.MonthStart = limitWindows(time.Now())
+	if u.Limits == nil && u.Fees == nil {
+		return model.LimitUsage{}, check, nil
+	}
+
+	usage, err := u.TransactionRepo.GetLimitUsage(ctx, userID, check.DayStart, check.MonthStart)
+	if err != nil {
+		return model.LimitUsage{}, check, err
+	}
+	check.Sender = &usage
+	return usage, check, nil
+}
+
+func (u *TransactionUsecase) checkTopUpLimit(usage model.LimitUsage, amount model.Money) error {
+	if u.Limits == nil {
+		return nil
+	}
+	return checkLimit(u.tierOf(usage).TopUp, usage.TopUp, amount)
+}
+
+// checkTransferLimits checks the sender's transfer-out and the recipient's transfer-in limits. The
+// recipient's usage is added to check.
+func (u *TransactionUsecase) checkTransferLimits(ctx context.Context, sender model.LimitUsage, req model.TransferRequest, check *model.UsageCheck) error {
+	if u.Limits == nil {
+		return nil
+	}
+	if err := checkLimit(u.tierOf(sender).TransferOut, sender.TransferOut, req.Amount); err != nil {
+		return err
+	}
+
+	recipient, err := u.TransactionRepo.GetLimitUsageByWalletNumber(ctx, req.TargetWalletNumber, check.DayStart, check.MonthStart)
+	if err != nil {
+		return err
+	}
+	if checkLimit(u.tierOf(recipient).TransferIn, recipient.TransferIn, req.Amount) != nil {
+		return ErrRecipientLimit
+	}
+	check.Recipient = &recipient
+	return nil
+}
+
+// GetLimits shows the user's limits and what is left of them in the current day and month.
+func (u *TransactionUsecase) GetLimits(ctx context.Context, userID int) (model.LimitsResponse, error) {
+	dayStart, monthStart := limitWindows(time.Now())
+	usage, err := u.TransactionRepo.GetLimitUsage(ctx, userID, dayStart, monthStart)
+	if err != nil {
+		return model.LimitsResponse{}, err
+	}
+
+	// without configured limits everything is shown as uncapped
+	var tier model.LimitTier
+	if u.Limits != nil {
+		tier = u.tierOf(usage)
+	}
+
+	status := func(limit model.Limit, used model.Usage) model.LimitStatus {
+		return model.LimitStatus{
+			PerTransaction: capOf(limit.PerTransaction),
+			Daily: model.LimitWindow{
+				Limit:     capOf(limit.Daily),
+				Used:      used.Daily,
+				Remaining: remainingOf(limit.Daily, used.Daily),
+				ResetsAt:  dayStart.AddDate(0, 0, 1),
+			},
+			Monthly: model.LimitWindow{
+				Limit:     capOf(limit.Monthly),
+				Used:      used.Monthly,
+				Remaining: remainingOf(limit.Monthly, used.Monthly),
+				ResetsAt:  monthStart.AddDate(0, 1, 0),
+			},
+		}
+	}
+
+	return model.LimitsResponse{
+		Tier:        usage.Tier,
+		TopUp:       status(tier.TopUp, usage.TopUp),
+		TransferOut: status(tier.TransferOut, usage.TransferOut),
+		TransferIn:  status(tier.TransferIn, usage.TransferIn),
+	}, nil
+}
+
+func capOf(limit model.Money) *model.Money {
+	if limit.IsZero() {
+		return nil
+	}
+	return &limit
+}
@@ -0,0 +1,276 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"ewallet-service/internal/repository/mocks"
+	"ewallet-service/internal/usecase"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// testLimits: verified users may top up 1.000.000 a time, 2.000.000 a day and 5.000.000 a month
+func testLimits() map[string]model.LimitTier {
+	return map[string]model.LimitTier{
+		model.TierUnverified: {
+			TopUp:       model.Limit{PerTransaction: model.NewMoney(100000)},
+			TransferOut: model.Limit{PerTransaction: model.NewMoney(100000)},
+			TransferIn:  model.Limit{Daily: model.NewMoney(200000)},
+		},
+		model.TierVerified: {
+			TopUp:       model.Limit{PerTransaction: model.NewMoney(1000000), Daily: model.NewMoney(2000000), Monthly: model.NewMoney(5000000)},
+			TransferOut: model.Limit{Daily: model.NewMoney(1000000)},
+		},
+	}
+}
+
+func newLimitedUsecase(repo *mocks.TransactionRepositoryMock) *usecase.TransactionUsecase {
+	u := usecase.NewTransactionUsecase(repo, verifierStub{})
+	u.Limits = testLimits()
+	return u
+}
+
+func TestTopUp_WithinLimits(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newLimitedUsecase(mockRepo)
+
+	usage := model.LimitUsage{Tier: model.TierVerified, TopUp: model.Usage{Daily: model.NewMoney(1000000), Monthly: model.NewMoney(4000000)}}
+	amount := model.NewMoney(1000000)
+	mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).Return(usage, nil)
+	mockRepo.On("CreateTopUp", mock.Anything, 1, amount, model.NewMoney(0), mock.Anything).Return(model.TopUpResponse{ID: 7}, nil)
+
+	// act
+	res, err := u.TopUp(context.Background(), 1, model.TopUpRequest{Amount: amount})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 7, res.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTopUp_UsageChangedMeanwhileIsCheckedAgain(t *testing.T) {
+	// arrange: a concurrent top-up commits before this one gets the lock
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newLimitedUsecase(mockRepo)
+
+	before := model.LimitUsage{Tier: model.TierVerified, TopUp: model.Usage{Daily: model.NewMoney(1000000)}}
+	after := model.LimitUsage{Tier: model.TierVerified, TopUp: model.Usage{Daily: model.NewMoney(1500000)}}
+	amount := model.NewMoney(1000000)
+	mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).Return(before, nil).Once()
+	mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).Return(after, nil).Once()
+	checkedAgainst := mock.MatchedBy(func(check model.UsageCheck) bool {
+		return check.Sender != nil && check.Sender.Equal(before)
+	})
+	mockRepo.On("CreateTopUp", mock.Anything, 1, amount, model.NewMoney(0), checkedAgainst).
+		Return(model.TopUpResponse{}, repository.ErrLimitUsageChanged).Once()
+
+	// act
+	_, err := u.TopUp(context.Background(), 1, model.TopUpRequest{Amount: amount})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrDailyLimit)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "CreateTopUp", 1)
+}
+
+func TestTopUp_LimitExceeded(t *testing.T) {
+	cases := []struct {
+		name      string
+		usage     model.Usage
+		amount    model.Money
+		want      error
+		remaining model.Money
+	}{
+		{"per transaction", model.Usage{}, model.NewMoney(1000001), usecase.ErrPerTransactionLimit, model.NewMoney(1000000)},
+		{"daily", model.Usage{Daily: model.NewMoney(1500000), Monthly: model.NewMoney(1500000)}, model.NewMoney(600000), usecase.ErrDailyLimit, model.NewMoney(500000)},
+		{"monthly", model.Usage{Daily: model.NewMoney(0), Monthly: model.NewMoney(4800000)}, model.NewMoney(300000), usecase.ErrMonthlyLimit, model.NewMoney(200000)},
+		{"already over", model.Usage{Daily: model.NewMoney(2500000), Monthly: model.NewMoney(2500000)}, model.NewMoney(10000), usecase.ErrDailyLimit, model.NewMoney(0)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			mockRepo := new(mocks.TransactionRepositoryMock)
+			u := newLimitedUsecase(mockRepo)
+			mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).
+				Return(model.LimitUsage{Tier: model.TierVerified, TopUp: tc.usage}, nil)
+
+			// act
+			_, err := u.TopUp(context.Background(), 1, model.TopUpRequest{Amount: tc.amount})
+
+			// assert
+			assert.ErrorIs(t, err, tc.want)
+			var exceeded *usecase.LimitExceededError
+			if assert.True(t, errors.As(err, &exceeded)) {
+				assert.Equal(t, tc.remaining, exceeded.Remaining)
+			}
+			mockRepo.AssertNotCalled(t, "CreateTopUp", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTopUp_UnknownTierGetsUnverifiedLimits(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newLimitedUsecase(mockRepo)
+	mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).Return(model.LimitUsage{Tier: "gold"}, nil)
+
+	// act
+	_, err := u.TopUp(context.Background(), 1, model.TopUpRequest{Amount: model.NewMoney(200000)})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrPerTransactionLimit)
+}
+
+func TestTransfer_SenderLimitExceeded(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newLimitedUsecase(mockRepo)
+	req := model.TransferRequest{TargetWalletNumber: "100999", Amount: model.NewMoney(300000), PIN: "123456"}
+
+	usage := model.LimitUsage{Tier: model.TierVerified, TransferOut: model.Usage{Daily: model.NewMoney(800000)}}
+	mockTargetWallet(mockRepo, "100999", 2)
+	mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).Return(usage, nil)
+
+	// act
+	_, err := u.Transfer(context.Background(), 1, req)
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrDailyLimit)
+	mockRepo.AssertNotCalled(t, "GetLimitUsageByWalletNumber", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransfer_RecipientLimitExceeded(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newLimitedUsecase(mockRepo)
+	req := model.TransferRequest{TargetWalletNumber: "100999", Amount: model.NewMoney(150000), PIN: "123456"}
+
+	mockTargetWallet(mockRepo, "100999", 2)
+	mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).Return(model.LimitUsage{Tier: model.TierVerified}, nil)
+	recipient := model.LimitUsage{Tier: model.TierUnverified, TransferIn: model.Usage{Daily: model.NewMoney(100000)}}
+	mockRepo.On("GetLimitUsageByWalletNumber", mock.Anything, "100999", mock.Anything, mock.Anything).Return(recipient, nil)
+
+	// act
+	_, err := u.Transfer(context.Background(), 1, req)
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrRecipientLimit)
+	var exceeded *usecase.LimitExceededError
+	assert.False(t, errors.As(err, &exceeded), "recipient's remaining limit must not leak")
+	mockRepo.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransfer_RepositoryChecksBothUsages(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newLimitedUsecase(mockRepo)
+	req := model.TransferRequest{TargetWalletNumber: "100999", Amount: model.NewMoney(50000), PIN: "123456"}
+
+	sender := model.LimitUsage{Tier: model.TierVerified, TransferOut: model.Usage{Daily: model.NewMoney(100000), MonthlyCount: 1}}
+	recipient := model.LimitUsage{Tier: model.TierUnverified, TransferIn: model.Usage{Daily: model.NewMoney(100000)}}
+	mockTargetWallet(mockRepo, "100999", 2)
+	mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).Return(sender, nil)
+	mockRepo.On("GetLimitUsageByWalletNumber", mock.Anything, "100999", mock.Anything, mock.Anything).Return(recipient, nil)
+	checked := mock.MatchedBy(func(check model.UsageCheck) bool {
+		return check.Sender != nil && check.Sender.Equal(sender) && check.Recipient != nil && check.Recipient.Equal(recipient)
+	})
+	mockRepo.On("Transfer", mock.Anything, 1, req, model.NewMoney(0), checked).Return(model.TransferResponse{ID: "TRX-1"}, nil)
+
+	// act
+	_, err := u.Transfer(context.Background(), 1, req)
+
+	// assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransfer_GivesUpAfterRepeatedUsageChanges(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newLimitedUsecase(mockRepo)
+	req := model.TransferRequest{TargetWalletNumber: "100999", Amount: model.NewMoney(50000), PIN: "123456"}
+
+	mockTargetWallet(mockRepo, "100999", 2)
+	mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).Return(model.LimitUsage{Tier: model.TierVerified}, nil)
+	mockRepo.On("GetLimitUsageByWalletNumber", mock.Anything, "100999", mock.Anything, mock.Anything).Return(model.LimitUsage{Tier: model.TierVerified}, nil)
+	mockRepo.On("Transfer", mock.Anything, 1, req, model.NewMoney(0), mock.Anything).Return(model.TransferResponse{}, repository.ErrLimitUsageChanged)
+
+	// act
+	_, err := u.Transfer(context.Background(), 1, req)
+
+	// assert
+	assert.ErrorIs(t, err, repository.ErrLimitUsageChanged)
+	mockRepo.AssertNumberOfCalls(t, "Transfer", 3)
+}
+
+func TestTransfer_UnknownRecipient(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newLimitedUsecase(mockRepo)
+	req := model.TransferRequest{TargetWalletNumber: "404404", Amount: model.NewMoney(50000), PIN: "123456"}
+
+	mockRepo.On("FindWalletByNumber", mock.Anything, "404404").Return(nil, repository.ErrRecipientNotFound)
+
+	// act
+	_, err := u.Transfer(context.Background(), 1, req)
+
+	// assert
+	assert.ErrorIs(t, err, repository.ErrRecipientNotFound)
+	mockRepo.AssertNotCalled(t, "GetLimitUsage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetLimits(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newLimitedUsecase(mockRepo)
+
+	usage := model.LimitUsage{Tier: model.TierVerified, TopUp: model.Usage{Daily: model.NewMoney(500000), Monthly: model.NewMoney(3000000)}}
+	mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).Return(usage, nil)
+
+	// act
+	res, err := u.GetLimits(context.Background(), 1)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, model.TierVerified, res.Tier)
+	assert.Equal(t, model.NewMoney(1000000), *res.TopUp.PerTransaction)
+	assert.Equal(t, model.NewMoney(1500000), *res.TopUp.Daily.Remaining)
+	assert.Equal(t, model.NewMoney(2000000), *res.TopUp.Monthly.Remaining)
+	assert.False(t, res.TopUp.Daily.ResetsAt.After(res.TopUp.Monthly.ResetsAt))
+
+	// verified users have no cap on incoming transfers in testLimits
+	assert.Nil(t, res.TransferIn.Daily.Limit)
+	assert.Nil(t, res.TransferIn.Daily.Remaining)
+}
+
+func TestLoadLimitTiers(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	tiers, err := usecase.LoadLimitTiers(write("ok.json", `{
+		"unverified": {"topup": {"per_transaction": "500000"}},
+		"verified": {"transfer_out": {"daily": "25000000.00", "monthly": 100000000}}
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, model.NewMoney(500000), tiers[model.TierUnverified].TopUp.PerTransaction)
+	assert.Equal(t, model.NewMoney(100000000), tiers[model.TierVerified].TransferOut.Monthly)
+
+	_, err = usecase.LoadLimitTiers(write("missing.json", `{"verified": {}}`))
+	assert.ErrorContains(t, err, "unverified")
+
+	_, err = usecase.LoadLimitTiers(write("bad.json", `{"verified": {"topup": {"daily": "abc"}}}`))
+	assert.Error(t, err)
+}
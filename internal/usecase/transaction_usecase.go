@@ -2,26 +2,301 @@ package usecase
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"ewallet-service/internal/model"
 	"ewallet-service/internal/repository"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const (
+	defaultHistoryLimit = 10
+	maxHistoryLimit     = 100
+
+	// a top-up or transfer is checked this often before repository.ErrLimitUsageChanged is given up on
+	maxUsageAttempts = 3
+)
+
+var (
+	ErrInvalidCursor = errors.New("Cursor tidak valid")
+	ErrInvalidFilter = errors.New("Filter tidak valid")
+	ErrOTPRequired   = errors.New("Transfer dengan nominal ini memerlukan kode OTP 2FA")
+)
+
+// PINVerifier checks a user's transaction PIN (implemented by UserUsecase).
+type PINVerifier interface {
+	VerifyPIN(ctx context.Context, userID int, pin string) error
+}
+
+// OTPVerifier checks a 2FA code of a user (implemented by UserUsecase).
+type OTPVerifier interface {
+	VerifyOTP(ctx context.Context, userID int, code string) error
+}
+
 type TransactionUsecase struct {
-	TransactionRepo repository.TransactionRepository
+	TransactionRepo    repository.TransactionRepository
+	PIN                PINVerifier
+	RequirePINForTopUp bool
+
+	// transfers above TwoFactorThreshold need an OTP as well, zero turns the check off
+	OTP                OTPVerifier
+	TwoFactorThreshold model.Money
+
+	// Limits caps top-ups and transfers per verification tier, nil turns the checks off
+	Limits map[string]model.LimitTier
+	// Fees prices top-ups and transfers per verification tier, nil makes everything free
+	Fees model.FeeSchedule
 }
 
-func NewTransactionUsecase(repo repository.TransactionRepository) *TransactionUsecase {
-	return &TransactionUsecase{TransactionRepo: repo}
+func NewTransactionUsecase(repo repository.TransactionRepository, pin PINVerifier) *TransactionUsecase {
+	return &TransactionUsecase{TransactionRepo: repo, PIN: pin}
 }
 
 func (u *TransactionUsecase) TopUp(ctx context.Context, userID int, req model.TopUpRequest) (model.TopUpResponse, error) {
-	return u.TransactionRepo.CreateTopUp(ctx, userID, req.Amount)
+	if u.RequirePINForTopUp {
+		if err := u.PIN.VerifyPIN(ctx, userID, req.PIN); err != nil {
+			return model.TopUpResponse{}, err
+		}
+	}
+	for attempt := 1; ; attempt++ {
+		res, err := u.topUp(ctx, userID, req)
+		if errors.Is(err, repository.ErrLimitUsageChanged) && attempt < maxUsageAttempts {
+			continue
+		}
+		return res, err
+	}
+}
+
+func (u *TransactionUsecase) topUp(ctx context.Context, userID int, req model.TopUpRequest) (model.TopUpResponse, error) {
+	usage, check, err := u.usage(ctx, userID)
+	if err != nil {
+		return model.TopUpResponse{}, err
+	}
+	if err := u.checkTopUpLimit(usage, req.Amount); err != nil {
+		return model.TopUpResponse{}, err
+	}
+	fee, err := u.topUpFee(usage, req.Amount)
+	if err != nil {
+		return model.TopUpResponse{}, err
+	}
+	return u.TransactionRepo.CreateTopUp(ctx, userID, req.Amount, fee, check)
 }
 
 func (u *TransactionUsecase) Transfer(ctx context.Context, senderID int, req model.TransferRequest) (model.TransferResponse, error) {
-	return u.TransactionRepo.Transfer(ctx, senderID, req)
+	return u.transfer(ctx, senderID, req, nil)
+}
+
+// transfer charges the current fee. When it confirms a quote, quotedFee is the most it may charge.
+func (u *TransactionUsecase) transfer(ctx context.Context, senderID int, req model.TransferRequest, quotedFee *model.Money) (model.TransferResponse, error) {
+	if err := u.PIN.VerifyPIN(ctx, senderID, req.PIN); err != nil {
+		return model.TransferResponse{}, err
+	}
+	if u.requiresOTP(req.Amount) {
+		if req.OTP == "" {
+			return model.TransferResponse{}, ErrOTPRequired
+		}
+		if err := u.OTP.VerifyOTP(ctx, senderID, req.OTP); err != nil {
+			return model.TransferResponse{}, err
+		}
+	}
+	target, err := u.TransactionRepo.FindWalletByNumber(ctx, req.TargetWalletNumber)
+	if err != nil {
+		return model.TransferResponse{}, err
+	}
+	if target.UserID == senderID {
+		// between the sender's own pockets: neither limited nor charged
+		return u.TransactionRepo.Transfer(ctx, senderID, req, model.NewMoney(0), model.UsageCheck{})
+	}
+
+	for attempt := 1; ; attempt++ {
+		res, err := u.limitedTransfer(ctx, senderID, req, quotedFee)
+		if errors.Is(err, repository.ErrLimitUsageChanged) && attempt < maxUsageAttempts {
+			continue
+		}
+		return res, err
+	}
+}
+
+// limitedTransfer is a transfer to another user, checked against both users' limits.
+func (u *TransactionUsecase) limitedTransfer(ctx context.Context, senderID int, req model.TransferRequest, quotedFee *model.Money) (model.TransferResponse, error) {
+	usage, check, err := u.usage(ctx, senderID)
+	if err != nil {
+		return model.TransferResponse{}, err
+	}
+	if err := u.checkTransferLimits(ctx, usage, req, &check); err != nil {
+		return model.TransferResponse{}, err
+	}
+	// the repository refuses the transfer when usage moved, so the fee cannot be priced on a free
+	// transfer that several quotes of the sender were all counting on
+	fee := u.transferFee(usage, req.Amount)
+	if quotedFee != nil && fee.Cmp(*quotedFee) > 0 {
+		return model.TransferResponse{}, ErrTransferQuoteFeeChanged
+	}
+	return u.TransactionRepo.Transfer(ctx, senderID, req, fee, check)
+}
+
+func (u *TransactionUsecase) requiresOTP(amount model.Money) bool {
+	return u.OTP != nil && !u.TwoFactorThreshold.IsZero() && amount.Cmp(u.TwoFactorThreshold) > 0
+}
+
+func (u *TransactionUsecase) GetHistory(ctx context.Context, userID int, filter model.TransactionFilter) (model.TransactionPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultHistoryLimit
+	}
+	if filter.Limit > maxHistoryLimit {
+		filter.Limit = maxHistoryLimit
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return model.TransactionPage{}, ErrInvalidFilter
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && filter.MaxAmount.LessThan(*filter.MinAmount) {
+		return model.TransactionPage{}, ErrInvalidFilter
+	}
+
+	if filter.Cursor != "" {
+		createdAt, id, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return model.TransactionPage{}, err
+		}
+		filter.AfterCreatedAt, filter.AfterID = createdAt, id
+	}
+
+	// fetch one extra row to know whether there is a next page
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	items, err := u.TransactionRepo.GetTransactionHistory(ctx, userID, filter)
+	if err != nil {
+		return model.TransactionPage{}, err
+	}
+
+	page := model.TransactionPage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		last := page.Items[limit-1]
+		page.NextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+	return page, nil
+}
+
+// the cursor is opaque to clients: base64url("<created_at RFC3339Nano>|<id>")
+func encodeCursor(createdAt time.Time, id int) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + strconv.Itoa(id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (time.Time, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	ts, idPart, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	id, err := strconv.Atoi(idPart)
+	if err != nil || id <= 0 {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	return createdAt, id, nil
+}
+
+func (u *TransactionUsecase) GetTransfer(ctx context.Context, userID int, reference string) (*model.Transfer, error) {
+	return u.TransactionRepo.FindTransferByReference(ctx, userID, reference)
+}
+
+func (u *TransactionUsecase) GetTransaction(ctx context.Context, userID int, id int) (*model.TransactionDetail, error) {
+	detail, err := u.TransactionRepo.FindTransactionByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if detail.BalanceAfter != nil {
+		before := *detail.BalanceAfter
+		switch detail.TransactionType {
+		case model.TransactionTypeTopUp, model.TransactionTypeTransferIn:
+			before = before.Sub(detail.Amount)
+		case model.TransactionTypeTransferOut, model.TransactionTypeFee:
+			before = before.Add(detail.Amount)
+		}
+		// ADJUSTMENT rows only correct the history, the balance did not move
+		detail.BalanceBefore = &before
+	}
+
+	if detail.Counterparty != nil {
+		detail.Counterparty.Name = maskName(detail.Counterparty.Name)
+	}
+	return detail, nil
 }
 
-func (u *TransactionUsecase) GetHistory(ctx context.Context, userID int) ([]model.Transaction, error) {
-	return u.TransactionRepo.GetTransactionHistory(ctx, userID)
+// maskName keeps the first letter of every word: "Budi Santoso" -> "B*** S******".
+func maskName(name string) string {
+	words := strings.Fields(name)
+	for i, w := range words {
+		runes := []rune(w)
+		for j := 1; j < len(runes); j++ {
+			runes[j] = '*'
+		}
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+var ErrInvalidPeriod = errors.New("Periode statement tidak valid")
+
+// GetStatement builds the statement of a calendar month ("2026-09") for one of the user's pockets;
+// an empty walletNumber means the primary one.
+func (u *TransactionUsecase) GetStatement(ctx context.Context, userID int, walletNumber, month string) (*model.Statement, error) {
+	from, err := time.Parse("2006-01", month)
+	if err != nil {
+		return nil, ErrInvalidPeriod
+	}
+	if from.After(time.Now()) {
+		return nil, ErrInvalidPeriod
+	}
+	to := from.AddDate(0, 1, 0)
+
+	st, transactions, err := u.TransactionRepo.GetStatement(ctx, userID, walletNumber, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := st.OpeningBalance
+	st.TotalIn = model.NewMoney(0)
+	st.TotalOut = model.NewMoney(0)
+	st.Lines = make([]model.StatementLine, 0, len(transactions))
+	for _, t := range transactions {
+		line := model.StatementLine{
+			TransactionID:   t.ID,
+			Date:            t.CreatedAt,
+			TransactionType: t.TransactionType,
+			Description:     t.Description,
+			Reference:       t.TransferRef,
+			Credit:          model.NewMoney(0),
+			Debit:           model.NewMoney(0),
+		}
+
+		signed := t.SignedAmount()
+		if signed.IsNegative() {
+			line.Debit = signed.Neg()
+			st.TotalOut = st.TotalOut.Add(line.Debit)
+		} else {
+			line.Credit = signed
+			st.TotalIn = st.TotalIn.Add(line.Credit)
+		}
+		balance = balance.Add(signed)
+		line.Balance = balance
+
+		st.Lines = append(st.Lines, line)
+	}
+
+	st.ClosingBalance = balance
+	st.GeneratedAt = time.Now()
+	return st, nil
 }
@@ -2,8 +2,8 @@ package usecase_test
 
 import (
 	"context"
-	"errors"
 	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
 	"ewallet-service/internal/repository/mocks"
 	"ewallet-service/internal/usecase"
 	"testing"
@@ -13,53 +13,73 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+// verifierStub accepts every PIN and OTP unless err is set
+type verifierStub struct {
+	err error
+}
+
+func (s verifierStub) VerifyPIN(ctx context.Context, userID int, pin string) error {
+	return s.err
+}
+
+func (s verifierStub) VerifyOTP(ctx context.Context, userID int, code string) error {
+	return s.err
+}
+
 func TestTopUp_Success(t *testing.T) {
 	// arrange
 	mockRepo := new(mocks.TransactionRepositoryMock)
-	u := usecase.NewTransactionUsecase(mockRepo)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{})
 
 	userID := 1
 	req := model.TopUpRequest{
-		Amount: 50000,
+		Amount: model.NewMoney(50000),
 	}
 
 	expectedRes := model.TopUpResponse{
 		ID:           1,
-		TopUpAmount:  50000,
-		BalanceAfter: 50000,
+		TopUpAmount:  model.NewMoney(50000),
+		BalanceAfter: model.NewMoney(50000),
 		WalletNumber: "1001",
 		CreatedAt:    time.Now(),
 	}
 
-	mockRepo.On("CreateTopUp", mock.Anything, userID, req.Amount).Return(expectedRes, nil)
+	mockRepo.On("CreateTopUp", mock.Anything, userID, req.Amount, model.NewMoney(0), mock.Anything).Return(expectedRes, nil)
 
 	// act
 	res, err := u.TopUp(context.Background(), userID, req)
 
 	// assert
 	assert.NoError(t, err)
-	assert.Equal(t, float64(50000), res.TopUpAmount)
+	assert.Equal(t, model.NewMoney(50000), res.TopUpAmount)
 	mockRepo.AssertExpectations(t)
 }
 
+// mockTargetWallet lets the transfer's recipient lookup find number, owned by ownerID.
+func mockTargetWallet(mockRepo *mocks.TransactionRepositoryMock, number string, ownerID int) {
+	wallet := &model.Recipient{UserID: ownerID, WalletNumber: number, Name: "Siti Aminah", Status: model.WalletActive}
+	mockRepo.On("FindWalletByNumber", mock.Anything, number).Return(wallet, nil)
+}
+
 func TestTransfer_Success(t *testing.T) {
 	// arrange
 	mockRepo := new(mocks.TransactionRepositoryMock)
-	u := usecase.NewTransactionUsecase(mockRepo)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{})
 
 	senderID := 1
 	req := model.TransferRequest{
 		TargetWalletNumber: "100999",
-		Amount:             25000,
+		Amount:             model.NewMoney(25000),
 	}
 
 	expectedRes := model.TransferResponse{
 		ID:             "TRX-123",
-		Amount:         25000,
+		Amount:         model.NewMoney(25000),
 		ReceiverWallet: "100999",
 	}
 
-	mockRepo.On("Transfer", mock.Anything, senderID, req).Return(expectedRes, nil)
+	mockTargetWallet(mockRepo, "100999", 2)
+	mockRepo.On("Transfer", mock.Anything, senderID, req, model.NewMoney(0), mock.Anything).Return(expectedRes, nil)
 
 	// act
 	res, err := u.Transfer(context.Background(), senderID, req)
@@ -73,32 +93,88 @@ func TestTransfer_Success(t *testing.T) {
 func TestTransfer_Failed_RepoError(t *testing.T) {
 	// arrange
 	mockRepo := new(mocks.TransactionRepositoryMock)
-	u := usecase.NewTransactionUsecase(mockRepo)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{})
 
 	senderID := 1
 	req := model.TransferRequest{
 		TargetWalletNumber: "100999",
-		Amount:             1000000,
+		Amount:             model.NewMoney(1000000),
 	}
 
-	expectedErr := errors.New("Saldo tidak mencukupi")
-	mockRepo.On("Transfer", mock.Anything, senderID, req).Return(model.TransferResponse{}, expectedErr)
+	mockTargetWallet(mockRepo, "100999", 2)
+	mockRepo.On("Transfer", mock.Anything, senderID, req, model.NewMoney(0), mock.Anything).Return(model.TransferResponse{}, repository.ErrInsufficientFunds)
 
 	// act
 	res, err := u.Transfer(context.Background(), senderID, req)
 
 	// assert
-	assert.Error(t, err)
-	assert.Equal(t, "Saldo tidak mencukupi", err.Error())
+	assert.ErrorIs(t, err, repository.ErrInsufficientFunds)
 	assert.Empty(t, res.ID)
 
 	mockRepo.AssertExpectations(t)
 }
 
+func TestTransfer_WrongPIN(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{err: usecase.ErrPINInvalid})
+
+	req := model.TransferRequest{
+		TargetWalletNumber: "100999",
+		Amount:             model.NewMoney(25000),
+		PIN:                "000000",
+	}
+
+	// act
+	res, err := u.Transfer(context.Background(), 1, req)
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrPINInvalid)
+	assert.Empty(t, res.ID)
+	mockRepo.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransfer_AboveThresholdNeedsOTP(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{})
+	u.OTP = verifierStub{}
+	u.TwoFactorThreshold = model.NewMoney(5000000)
+
+	small := model.TransferRequest{TargetWalletNumber: "100999", Amount: model.NewMoney(5000000), PIN: "123456"}
+	large := model.TransferRequest{TargetWalletNumber: "100999", Amount: model.NewMoney(5000001), PIN: "123456"}
+
+	mockTargetWallet(mockRepo, "100999", 2)
+	mockRepo.On("Transfer", mock.Anything, 1, small, model.NewMoney(0), mock.Anything).Return(model.TransferResponse{ID: "TRX-1"}, nil)
+
+	// act
+	_, errSmall := u.Transfer(context.Background(), 1, small)
+	_, errLarge := u.Transfer(context.Background(), 1, large)
+
+	// assert
+	assert.NoError(t, errSmall)
+	assert.ErrorIs(t, errLarge, usecase.ErrOTPRequired)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTopUp_RequirePIN(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{err: usecase.ErrPINNotSet})
+	u.RequirePINForTopUp = true
+
+	// act
+	_, err := u.TopUp(context.Background(), 1, model.TopUpRequest{Amount: model.NewMoney(50000)})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrPINNotSet)
+	mockRepo.AssertNotCalled(t, "CreateTopUp", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestGetHistory_Success(t *testing.T) {
 	// arrange
 	mockRepo := new(mocks.TransactionRepositoryMock)
-	u := usecase.NewTransactionUsecase(mockRepo)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{})
 
 	userID := 1
 
@@ -106,29 +182,30 @@ func TestGetHistory_Success(t *testing.T) {
 	expectedHistory := []model.Transaction{
 		{
 			ID:              1,
-			Amount:          50000,
+			Amount:          model.NewMoney(50000),
 			TransactionType: "TOPUP",
 			Description:     "Topup Saldo",
 		},
 		{
 			ID:              2,
-			Amount:          20000,
+			Amount:          model.NewMoney(20000),
 			TransactionType: "TRANSFER_OUT",
 			Description:     "Bayar hutang",
 		},
 	}
 
 	// mocking
-	mockRepo.On("GetTransactionHistory", mock.Anything, userID).Return(expectedHistory, nil)
+	mockRepo.On("GetTransactionHistory", mock.Anything, userID, model.TransactionFilter{Limit: 11}).Return(expectedHistory, nil)
 
 	// act
-	res, err := u.GetHistory(context.Background(), userID)
+	res, err := u.GetHistory(context.Background(), userID, model.TransactionFilter{})
 
 	// assert
 	assert.NoError(t, err)
-	assert.Len(t, res, 2)
-	assert.Equal(t, "TOPUP", res[0].TransactionType)
-	assert.Equal(t, float64(20000), res[1].Amount)
+	assert.Len(t, res.Items, 2)
+	assert.Equal(t, "TOPUP", res.Items[0].TransactionType)
+	assert.Equal(t, "20000.00", res.Items[1].Amount.String())
+	assert.Empty(t, res.NextCursor)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -136,18 +213,223 @@ func TestGetHistory_Success(t *testing.T) {
 func TestGetHistory_Empty(t *testing.T) {
 	// arrange
 	mockRepo := new(mocks.TransactionRepositoryMock)
-	u := usecase.NewTransactionUsecase(mockRepo)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{})
 
 	userID := 2
 	expectedHistory := []model.Transaction{}
 
-	mockRepo.On("GetTransactionHistory", mock.Anything, userID).Return(expectedHistory, nil)
+	mockRepo.On("GetTransactionHistory", mock.Anything, userID, mock.AnythingOfType("model.TransactionFilter")).Return(expectedHistory, nil)
 
 	// act
-	res, err := u.GetHistory(context.Background(), userID)
+	res, err := u.GetHistory(context.Background(), userID, model.TransactionFilter{})
 
 	// assert
 	assert.NoError(t, err)
-	assert.Len(t, res, 0)
-	assert.NotNil(t, res)
+	assert.Len(t, res.Items, 0)
+	assert.NotNil(t, res.Items)
+}
+
+func TestGetHistory_NextPage(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{})
+
+	userID := 1
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	firstPage := []model.Transaction{
+		{ID: 9, CreatedAt: base},
+		{ID: 8, CreatedAt: base.Add(-time.Minute)},
+		{ID: 7, CreatedAt: base.Add(-2 * time.Minute)},
+	}
+
+	mockRepo.On("GetTransactionHistory", mock.Anything, userID, model.TransactionFilter{Limit: 3}).Return(firstPage, nil)
+
+	// act
+	res, err := u.GetHistory(context.Background(), userID, model.TransactionFilter{Limit: 2})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, res.Items, 2)
+	assert.NotEmpty(t, res.NextCursor)
+
+	// the cursor points right after the last returned row
+	mockRepo.On("GetTransactionHistory", mock.Anything, userID, mock.MatchedBy(func(f model.TransactionFilter) bool {
+		return f.AfterID == 8 && f.AfterCreatedAt.Equal(base.Add(-time.Minute))
+	})).Return([]model.Transaction{firstPage[2]}, nil)
+
+	next, err := u.GetHistory(context.Background(), userID, model.TransactionFilter{Limit: 2, Cursor: res.NextCursor})
+
+	assert.NoError(t, err)
+	assert.Len(t, next.Items, 1)
+	assert.Empty(t, next.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetHistory_InvalidCursor(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{})
+
+	// act
+	_, err := u.GetHistory(context.Background(), 1, model.TransactionFilter{Cursor: "not-a-cursor"})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrInvalidCursor)
+	mockRepo.AssertNotCalled(t, "GetTransactionHistory", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetHistory_InvalidAmountRange(t *testing.T) {
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{})
+
+	min, max := model.NewMoney(50000), model.NewMoney(10000)
+
+	_, err := u.GetHistory(context.Background(), 1, model.TransactionFilter{MinAmount: &min, MaxAmount: &max})
+
+	assert.ErrorIs(t, err, usecase.ErrInvalidFilter)
+}
+
+func TestGetTransfer_Success(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{})
+
+	userID := 1
+	reference := "01JAZ3N6W5Q2K8X4T7R9M1B0CD"
+	expected := &model.Transfer{
+		Reference:      reference,
+		SenderWallet:   "100111",
+		ReceiverWallet: "100999",
+		Amount:         model.NewMoney(25000),
+	}
+
+	mockRepo.On("FindTransferByReference", mock.Anything, userID, reference).Return(expected, nil)
+
+	// act
+	res, err := u.GetTransfer(context.Background(), userID, reference)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, reference, res.Reference)
+	assert.Equal(t, "100999", res.ReceiverWallet)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetTransfer_NotOwned(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{})
+
+	reference := "01JAZ3N6W5Q2K8X4T7R9M1B0CD"
+	mockRepo.On("FindTransferByReference", mock.Anything, 2, reference).Return(nil, repository.ErrTransferNotFound)
+
+	// act
+	res, err := u.GetTransfer(context.Background(), 2, reference)
+
+	// assert
+	assert.ErrorIs(t, err, repository.ErrTransferNotFound)
+	assert.Nil(t, res)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetTransaction_TransferIn(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{})
+
+	balanceAfter := model.NewMoney(75000)
+	detail := &model.TransactionDetail{
+		Transaction: model.Transaction{
+			ID:              5,
+			TransactionType: model.TransactionTypeTransferIn,
+			Amount:          model.NewMoney(25000),
+			TransferRef:     "01JAZ3N6W5Q2K8X4T7R9M1B0CD",
+		},
+		BalanceAfter: &balanceAfter,
+		Counterparty: &model.Counterparty{WalletNumber: "100111", Name: "Budi Santoso"},
+	}
+
+	mockRepo.On("FindTransactionByID", mock.Anything, 1, 5).Return(detail, nil)
+
+	// act
+	res, err := u.GetTransaction(context.Background(), 1, 5)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "50000.00", res.BalanceBefore.String())
+	assert.Equal(t, "100111", res.Counterparty.WalletNumber)
+	assert.Equal(t, "B*** S******", res.Counterparty.Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetTransaction_NotOwned(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{})
+
+	mockRepo.On("FindTransactionByID", mock.Anything, 2, 5).Return(nil, repository.ErrTransactionNotFound)
+
+	// act
+	res, err := u.GetTransaction(context.Background(), 2, 5)
+
+	// assert
+	assert.ErrorIs(t, err, repository.ErrTransactionNotFound)
+	assert.Nil(t, res)
+}
+
+func TestGetStatement_RunningBalance(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{})
+
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	header := &model.Statement{WalletNumber: "100111", OpeningBalance: model.NewMoney(10000)}
+	rows := []model.Transaction{
+		{ID: 1, TransactionType: model.TransactionTypeTopUp, Amount: model.NewMoney(50000)},
+		{ID: 2, TransactionType: model.TransactionTypeTransferOut, Amount: model.NewMoney(20000)},
+		{ID: 3, TransactionType: model.TransactionTypeTransferIn, Amount: model.NewMoney(5000)},
+	}
+
+	mockRepo.On("GetStatement", mock.Anything, 1, "", from, to).Return(header, rows, nil)
+
+	// act
+	st, err := u.GetStatement(context.Background(), 1, "", "2026-09")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, st.Lines, 3)
+	assert.Equal(t, "60000.00", st.Lines[0].Balance.String())
+	assert.Equal(t, "20000.00", st.Lines[1].Debit.String())
+	assert.Equal(t, "40000.00", st.Lines[1].Balance.String())
+	assert.Equal(t, "55000.00", st.TotalIn.String())
+	assert.Equal(t, "20000.00", st.TotalOut.String())
+	assert.Equal(t, "45000.00", st.ClosingBalance.String())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetStatement_ForeignPocket(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{})
+
+	mockRepo.On("GetStatement", mock.Anything, 1, "100999", mock.Anything, mock.Anything).Return(nil, nil, repository.ErrWalletNotFound)
+
+	// act
+	_, err := u.GetStatement(context.Background(), 1, "100999", "2026-09")
+
+	// assert
+	assert.ErrorIs(t, err, repository.ErrWalletNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetStatement_InvalidMonth(t *testing.T) {
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{})
+
+	_, err := u.GetStatement(context.Background(), 1, "", "2026-13")
+	assert.ErrorIs(t, err, usecase.ErrInvalidPeriod)
+
+	_, err = u.GetStatement(context.Background(), 1, "", time.Now().AddDate(0, 2, 0).Format("2006-01"))
+	assert.ErrorIs(t, err, usecase.ErrInvalidPeriod)
 }
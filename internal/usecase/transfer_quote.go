@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ewallet-service/internal/model"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// a quote only has to survive the confirmation screen
+const transferQuoteTTL = 5 * time.Minute
+
+var ErrTransferQuoteFeeChanged = errors.New("Biaya transfer naik sejak quote dibuat, buat quote baru")
+
+// QuoteTransfer checks the recipient and the limits like Transfer would and stores a quote that
+// shows the sender who they are paying (name masked) and what it costs, before money moves.
+func (u *TransactionUsecase) QuoteTransfer(ctx context.Context, senderID int, req model.TransferQuoteRequest) (*model.TransferQuote, error) {
+	recipient, err := u.TransactionRepo.FindRecipient(ctx, senderID, req.SourceWalletNumber, req.TargetWalletNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// a move between the sender's own pockets is neither limited nor charged
+	fee := model.NewMoney(0)
+	if recipient.UserID != senderID {
+		usage, check, err := u.usage(ctx, senderID)
+		if err != nil {
+			return nil, err
+		}
+		transfer := model.TransferRequest{TargetWalletNumber: req.TargetWalletNumber, Amount: req.Amount}
+		if err := u.checkTransferLimits(ctx, usage, transfer, &check); err != nil {
+			return nil, err
+		}
+		fee = u.transferFee(usage, req.Amount)
+	}
+
+	quote := &model.TransferQuote{
+		ID:                 ulid.Make().String(),
+		UserID:             senderID,
+		SourceWalletNumber: req.SourceWalletNumber,
+		TargetWalletNumber: recipient.WalletNumber,
+		RecipientName:      maskName(recipient.Name),
+		Amount:             req.Amount,
+		Fee:                fee,
+		Total:              req.Amount.Add(fee),
+		Description:        req.Description,
+		ExpiresAt:          time.Now().Add(transferQuoteTTL),
+	}
+	if err := u.TransactionRepo.CreateTransferQuote(ctx, quote); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// ConfirmTransfer executes a quote of the sender. PIN, OTP, limits, the recipient and the fee are
+// checked again; the quoted fee is the most that is charged, a higher fee refuses the confirm. The
+// quote is used up together with the transfer.
+func (u *TransactionUsecase) ConfirmTransfer(ctx context.Context, senderID int, req model.TransferConfirmRequest) (model.TransferResponse, error) {
+	quote, err := u.TransactionRepo.FindTransferQuote(ctx, senderID, req.QuoteID)
+	if err != nil {
+		return model.TransferResponse{}, err
+	}
+
+	transfer := model.TransferRequest{
+		SourceWalletNumber: quote.SourceWalletNumber,
+		TargetWalletNumber: quote.TargetWalletNumber,
+		Amount:             quote.Amount,
+		Description:        quote.Description,
+		PIN:                req.PIN,
+		OTP:                req.OTP,
+		QuoteID:            quote.ID,
+	}
+	return u.transfer(ctx, senderID, transfer, &quote.Fee)
+}
+
+// InquireWallet lets a user check a wallet number before paying into it: the owner's masked
+// name, whether it can receive and whether it is one of the user's own pockets. The route is
+// rate limited, as it reveals who owns a number.
+func (u *TransactionUsecase) InquireWallet(ctx context.Context, userID int, walletNumber string) (*model.WalletInquiry, error) {
+	wallet, err := u.TransactionRepo.FindWalletByNumber(ctx, walletNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	own := wallet.UserID == userID
+	return &model.WalletInquiry{
+		WalletNumber: wallet.WalletNumber,
+		OwnerName:    maskName(wallet.Name),
+		CanReceive:   model.WalletCanCredit(wallet.Status),
+		OwnWallet:    own,
+	}, nil
+}
@@ -0,0 +1,229 @@
+package usecase_test
+
+import (
+	"context"
+	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
+	"ewallet-service/internal/repository/mocks"
+	"ewallet-service/internal/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestQuoteTransfer_Success(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newFeeUsecase(mockRepo)
+	req := model.TransferQuoteRequest{TargetWalletNumber: "100999", Amount: model.NewMoney(50000), Description: "makan siang"}
+
+	recipient := &model.Recipient{WalletID: 9, UserID: 2, WalletNumber: "100999", Name: "Siti Aminah", Status: model.WalletActive}
+	mockRepo.On("FindRecipient", mock.Anything, 1, "", "100999").Return(recipient, nil)
+	mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).Return(model.LimitUsage{Tier: model.TierUnverified}, nil)
+	mockRepo.On("CreateTransferQuote", mock.Anything, mock.AnythingOfType("*model.TransferQuote")).Return(nil)
+
+	// act
+	quote, err := u.QuoteTransfer(context.Background(), 1, req)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, quote.ID, 26)
+	assert.Equal(t, 1, quote.UserID)
+	assert.Equal(t, "S*** A*****", quote.RecipientName)
+	assert.Equal(t, model.NewMoney(2500), quote.Fee)
+	assert.Equal(t, model.NewMoney(52500), quote.Total)
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), quote.ExpiresAt, time.Second)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestQuoteTransfer_OwnPocketIsFree(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newFeeUsecase(mockRepo)
+	req := model.TransferQuoteRequest{SourceWalletNumber: "100123456780", TargetWalletNumber: "100999", Amount: model.NewMoney(50000)}
+
+	pocket := &model.Recipient{WalletID: 9, UserID: 1, WalletNumber: "100999", Name: "Budi Santoso", Status: model.WalletActive}
+	mockRepo.On("FindRecipient", mock.Anything, 1, "100123456780", "100999").Return(pocket, nil)
+	mockRepo.On("CreateTransferQuote", mock.Anything, mock.AnythingOfType("*model.TransferQuote")).Return(nil)
+
+	// act
+	quote, err := u.QuoteTransfer(context.Background(), 1, req)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "100123456780", quote.SourceWalletNumber)
+	assert.Equal(t, model.NewMoney(0), quote.Fee)
+	assert.Equal(t, model.NewMoney(50000), quote.Total)
+	mockRepo.AssertNotCalled(t, "GetLimitUsage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestQuoteTransfer_InvalidRecipient(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newFeeUsecase(mockRepo)
+	req := model.TransferQuoteRequest{TargetWalletNumber: "100999", Amount: model.NewMoney(50000)}
+	mockRepo.On("FindRecipient", mock.Anything, 1, "", "100999").Return(nil, repository.ErrRecipientWalletUnavailable)
+
+	// act
+	quote, err := u.QuoteTransfer(context.Background(), 1, req)
+
+	// assert
+	assert.ErrorIs(t, err, repository.ErrRecipientWalletUnavailable)
+	assert.Nil(t, quote)
+	mockRepo.AssertNotCalled(t, "CreateTransferQuote", mock.Anything, mock.Anything)
+}
+
+func TestConfirmTransfer_FeeWentDownChargesLess(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newFeeUsecase(mockRepo)
+
+	quote := &model.TransferQuote{
+		ID: "01JAAAAAAAAAAAAAAAAAAAAAAA", UserID: 1, TargetWalletNumber: "100999",
+		Amount: model.NewMoney(50000), Fee: model.NewMoney(2500), Description: "makan siang",
+	}
+	mockRepo.On("FindTransferQuote", mock.Anything, 1, quote.ID).Return(quote, nil)
+	// the sender verified their email since the quote and has free transfers now
+	mockTargetWallet(mockRepo, "100999", 2)
+	mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).Return(model.LimitUsage{Tier: model.TierVerified}, nil)
+
+	expected := model.TransferRequest{
+		TargetWalletNumber: "100999", Amount: model.NewMoney(50000), Description: "makan siang",
+		PIN: "123456", QuoteID: quote.ID,
+	}
+	mockRepo.On("Transfer", mock.Anything, 1, expected, model.NewMoney(0), mock.Anything).Return(model.TransferResponse{ID: "TRX-1"}, nil)
+
+	// act
+	res, err := u.ConfirmTransfer(context.Background(), 1, model.TransferConfirmRequest{QuoteID: quote.ID, PIN: "123456"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "TRX-1", res.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestConfirmTransfer_FeeWentUpIsRefused(t *testing.T) {
+	// arrange: two quotes were both priced on the last free transfer, the other one was confirmed first
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newFeeUsecase(mockRepo)
+
+	quote := &model.TransferQuote{
+		ID: "01JAAAAAAAAAAAAAAAAAAAAAAA", UserID: 1, TargetWalletNumber: "100999",
+		Amount: model.NewMoney(50000), Fee: model.NewMoney(0),
+	}
+	mockRepo.On("FindTransferQuote", mock.Anything, 1, quote.ID).Return(quote, nil)
+	mockTargetWallet(mockRepo, "100999", 2)
+	usage := model.LimitUsage{Tier: model.TierVerified, TransferOut: model.Usage{MonthlyCount: 3}}
+	mockRepo.On("GetLimitUsage", mock.Anything, 1, mock.Anything, mock.Anything).Return(usage, nil)
+
+	// act
+	_, err := u.ConfirmTransfer(context.Background(), 1, model.TransferConfirmRequest{QuoteID: quote.ID, PIN: "123456"})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrTransferQuoteFeeChanged)
+	mockRepo.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConfirmTransfer_InvalidQuote(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newFeeUsecase(mockRepo)
+	mockRepo.On("FindTransferQuote", mock.Anything, 1, "01JAAAAAAAAAAAAAAAAAAAAAAA").Return(nil, repository.ErrTransferQuoteInvalid)
+
+	// act
+	_, err := u.ConfirmTransfer(context.Background(), 1, model.TransferConfirmRequest{QuoteID: "01JAAAAAAAAAAAAAAAAAAAAAAA", PIN: "123456"})
+
+	// assert
+	assert.ErrorIs(t, err, repository.ErrTransferQuoteInvalid)
+	mockRepo.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConfirmTransfer_WrongPIN(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := usecase.NewTransactionUsecase(mockRepo, verifierStub{err: usecase.ErrPINInvalid})
+	quote := &model.TransferQuote{ID: "01JAAAAAAAAAAAAAAAAAAAAAAA", UserID: 1, TargetWalletNumber: "100999", Amount: model.NewMoney(50000)}
+	mockRepo.On("FindTransferQuote", mock.Anything, 1, quote.ID).Return(quote, nil)
+
+	// act
+	_, err := u.ConfirmTransfer(context.Background(), 1, model.TransferConfirmRequest{QuoteID: quote.ID, PIN: "000000"})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrPINInvalid)
+	mockRepo.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInquireWallet_MasksName(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newFeeUsecase(mockRepo)
+	wallet := &model.Recipient{WalletID: 9, UserID: 2, WalletNumber: "100999", Name: "Siti Aminah", Status: model.WalletActive}
+	mockRepo.On("FindWalletByNumber", mock.Anything, "100999").Return(wallet, nil)
+
+	// act
+	res, err := u.InquireWallet(context.Background(), 1, "100999")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, &model.WalletInquiry{WalletNumber: "100999", OwnerName: "S*** A*****", CanReceive: true}, res)
+}
+
+func TestInquireWallet_CannotReceive(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+	}{
+		{"frozen", model.WalletFrozen},
+		{"credit blocked", model.WalletCreditBlocked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			mockRepo := new(mocks.TransactionRepositoryMock)
+			u := newFeeUsecase(mockRepo)
+			wallet := &model.Recipient{UserID: 2, WalletNumber: "100999", Name: "Siti", Status: tt.status}
+			mockRepo.On("FindWalletByNumber", mock.Anything, "100999").Return(wallet, nil)
+
+			// act
+			res, err := u.InquireWallet(context.Background(), 1, "100999")
+
+			// assert
+			assert.NoError(t, err)
+			assert.False(t, res.CanReceive)
+			assert.False(t, res.OwnWallet)
+		})
+	}
+}
+
+func TestInquireWallet_OwnPocket(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newFeeUsecase(mockRepo)
+	wallet := &model.Recipient{UserID: 1, WalletNumber: "100999", Name: "Budi", Status: model.WalletActive}
+	mockRepo.On("FindWalletByNumber", mock.Anything, "100999").Return(wallet, nil)
+
+	// act
+	res, err := u.InquireWallet(context.Background(), 1, "100999")
+
+	// assert
+	assert.NoError(t, err)
+	assert.True(t, res.CanReceive)
+	assert.True(t, res.OwnWallet)
+}
+
+func TestInquireWallet_UnknownNumber(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.TransactionRepositoryMock)
+	u := newFeeUsecase(mockRepo)
+	mockRepo.On("FindWalletByNumber", mock.Anything, "100999").Return(nil, repository.ErrRecipientNotFound)
+
+	// act
+	res, err := u.InquireWallet(context.Background(), 1, "100999")
+
+	// assert
+	assert.ErrorIs(t, err, repository.ErrRecipientNotFound)
+	assert.Nil(t, res)
+}
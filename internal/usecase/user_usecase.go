@@ -2,23 +2,59 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base32"
 	"errors"
 	"ewallet-service/internal/model"
 	"ewallet-service/internal/repository"
-	"fmt"
+	"ewallet-service/internal/totp"
+	"log"
 	"os"
+	"strings"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	maxPINAttempts  = 5
+	pinLockDuration = 30 * time.Minute
+
+	maxOTPAttempts  = 5
+	otpLockDuration = 30 * time.Minute
+
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrPINNotSet     = errors.New("PIN transaksi belum dibuat")
+	ErrPINAlreadySet = errors.New("PIN transaksi sudah dibuat, gunakan ubah PIN")
+	ErrPINInvalid    = errors.New("PIN transaksi salah")
+	ErrPINLocked     = repository.ErrPINLocked
+	ErrWrongPassword = errors.New("Password salah")
+	ErrAccountFrozen = repository.ErrAccountFrozen
+	// ErrInvalidCredentials does not say whether the email or the password was wrong
+	ErrInvalidCredentials = errors.New("Email atau password salah")
+
+	ErrTwoFactorAlreadyEnabled = errors.New("2FA sudah aktif")
+	ErrTwoFactorNotEnrolled    = errors.New("2FA belum didaftarkan, lakukan enroll terlebih dahulu")
+	ErrTwoFactorNotEnabled     = errors.New("2FA belum aktif")
+	ErrOTPInvalid              = errors.New("Kode OTP salah atau sudah dipakai")
+	ErrOTPLocked               = repository.ErrOTPLocked
+	ErrRecoveryCodeInvalid     = errors.New("Recovery code salah atau sudah dipakai")
+)
+
 type UserUsecase struct {
 	UserRepo repository.UserRepository
+	Auth     *AuthUsecase
+	// Attempts throttles failed logins, optional
+	Attempts *LoginAttemptUsecase
+	// Account sends the verification email after Register, optional
+	Account *AccountUsecase
 }
 
-func NewUserUsecase(repo repository.UserRepository) *UserUsecase {
-	return &UserUsecase{UserRepo: repo}
+func NewUserUsecase(repo repository.UserRepository, auth *AuthUsecase) *UserUsecase {
+	return &UserUsecase{UserRepo: repo, Auth: auth}
 }
 
 func (u *UserUsecase) Register(ctx context.Context, req model.RegisterRequest) (model.RegisterResponse, error) {
@@ -28,7 +64,7 @@ func (u *UserUsecase) Register(ctx context.Context, req model.RegisterRequest) (
 		return model.RegisterResponse{}, err
 	}
 	if emailExists {
-		return model.RegisterResponse{}, fmt.Errorf("Email %s sudah terdaftar", req.Email)
+		return model.RegisterResponse{}, repository.ErrEmailTaken
 	}
 
 	// hash password
@@ -48,6 +84,13 @@ func (u *UserUsecase) Register(ctx context.Context, req model.RegisterRequest) (
 		return model.RegisterResponse{}, err
 	}
 
+	// the account exists either way; the user can ask for a new email later
+	if u.Account != nil {
+		if err := u.Account.SendVerification(ctx, newUser); err != nil {
+			log.Printf("Gagal mengirim email verifikasi ke %s: %v", newUser.Email, err)
+		}
+	}
+
 	return model.RegisterResponse{
 		ID:           newUser.ID,
 		Name:         newUser.Name,
@@ -58,42 +101,342 @@ func (u *UserUsecase) Register(ctx context.Context, req model.RegisterRequest) (
 }
 
 func (u *UserUsecase) Login(ctx context.Context, req model.LoginRequest) (model.LoginResponse, error) {
+	if err := u.checkAttempts(ctx, req.Email, req.ClientIP); err != nil {
+		return model.LoginResponse{}, err
+	}
+
 	// search user by email
 	user, err := u.UserRepo.FindByEmail(ctx, req.Email)
 	if err != nil {
-		return model.LoginResponse{}, errors.New("Email atau password salah")
+		return model.LoginResponse{}, u.loginFailed(ctx, req.Email, req.ClientIP, ErrInvalidCredentials)
 	}
 
 	// check password (hash vs plain)
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
-		return model.LoginResponse{}, errors.New("Email atau password salah")
+		return model.LoginResponse{}, u.loginFailed(ctx, req.Email, req.ClientIP, ErrInvalidCredentials)
+	}
+
+	// only told after the password matched, so it says nothing about unknown emails
+	if user.Frozen {
+		return model.LoginResponse{}, ErrAccountFrozen
 	}
 
-	// generate jwt token
-	secretKey := []byte(os.Getenv("JWT_SECRET"))
+	// with 2FA the password step only returns a challenge for POST /login/2fa,
+	// the counter is cleared once the second step passes too
+	if user.TOTPEnabled {
+		challenge, err := u.Auth.IssueChallenge(user)
+		if err != nil {
+			return model.LoginResponse{}, err
+		}
+		return model.LoginResponse{TwoFactorRequired: true, ChallengeToken: challenge}, nil
+	}
 
-	claims := jwt.MapClaims{
-		"user_id": user.ID,
-		"email":   user.Email,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
+	if err := u.loginSucceeded(ctx, user.Email); err != nil {
+		return model.LoginResponse{}, err
 	}
 
-	// token with method HS256
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	// access token + refresh token (new session)
+	return u.Auth.IssueTokens(ctx, user)
+}
 
-	signedToken, err := token.SignedString(secretKey)
+// LoginTwoFactor finishes a 2FA login with the current OTP or a recovery code.
+// Wrong codes count as failed logins of the account, like wrong passwords.
+// The challenge token is single-use: it is spent once the code was accepted.
+func (u *UserUsecase) LoginTwoFactor(ctx context.Context, req model.LoginTwoFactorRequest) (model.LoginResponse, error) {
+	challenge, err := u.Auth.ParseChallenge(req.ChallengeToken)
 	if err != nil {
-		return model.LoginResponse{}, fmt.Errorf("Gagal generate token: %v", err)
+		return model.LoginResponse{}, err
 	}
+	userID := challenge.UserID
 
-	return model.LoginResponse{
-		AccessToken: signedToken,
-		Type:        "Bearer",
-		ExpiresIn:   "24h",
-	}, nil
+	user, err := u.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		return model.LoginResponse{}, err
+	}
+	if user.Frozen {
+		return model.LoginResponse{}, ErrAccountFrozen
+	}
+	if err := u.checkAttempts(ctx, user.Email, req.ClientIP); err != nil {
+		return model.LoginResponse{}, err
+	}
+
+	if req.RecoveryCode != "" {
+		// 2FA may have been disabled since the challenge was issued, its codes are void then
+		state, err := u.UserRepo.GetTOTPState(ctx, userID)
+		if err != nil {
+			return model.LoginResponse{}, err
+		}
+		if !state.Enabled {
+			return model.LoginResponse{}, ErrTwoFactorNotEnabled
+		}
+
+		ok, err := u.UserRepo.UseRecoveryCode(ctx, userID, hashToken(normalizeRecoveryCode(req.RecoveryCode)))
+		if err != nil {
+			return model.LoginResponse{}, err
+		}
+		if !ok {
+			return model.LoginResponse{}, u.loginFailed(ctx, user.Email, req.ClientIP, ErrRecoveryCodeInvalid)
+		}
+	} else if err := u.VerifyOTP(ctx, userID, req.OTP); err != nil {
+		if errors.Is(err, ErrOTPInvalid) {
+			return model.LoginResponse{}, u.loginFailed(ctx, user.Email, req.ClientIP, err)
+		}
+		return model.LoginResponse{}, err
+	}
+
+	if err := u.Auth.ConsumeChallenge(ctx, challenge); err != nil {
+		return model.LoginResponse{}, err
+	}
+	if err := u.loginSucceeded(ctx, user.Email); err != nil {
+		return model.LoginResponse{}, err
+	}
+	return u.Auth.IssueTokens(ctx, user)
+}
+
+// the attempt helpers are no-ops when no LoginAttemptUsecase is wired in
+
+func (u *UserUsecase) checkAttempts(ctx context.Context, email, ip string) error {
+	if u.Attempts == nil {
+		return nil
+	}
+	return u.Attempts.Check(ctx, email, ip)
+}
+
+// loginFailed records the failure and returns cause, unless recording itself failed.
+func (u *UserUsecase) loginFailed(ctx context.Context, email, ip string, cause error) error {
+	if u.Attempts == nil {
+		return cause
+	}
+	if err := u.Attempts.Failure(ctx, email, ip); err != nil {
+		return err
+	}
+	return cause
+}
+
+func (u *UserUsecase) loginSucceeded(ctx context.Context, email string) error {
+	if u.Attempts == nil {
+		return nil
+	}
+	return u.Attempts.Success(ctx, email)
+}
+
+func (u *UserUsecase) RefreshToken(ctx context.Context, req model.RefreshTokenRequest) (model.LoginResponse, error) {
+	return u.Auth.Refresh(ctx, req.RefreshToken)
+}
+
+func (u *UserUsecase) Logout(ctx context.Context, claims *model.AccessClaims) error {
+	return u.Auth.Logout(ctx, claims)
 }
 
 func (u *UserUsecase) GetBalance(ctx context.Context, userID int) (*model.Wallet, error) {
 	return u.UserRepo.FindWalletByUserID(ctx, userID)
 }
+
+// SetPIN creates the transaction PIN. The account password is required so a stolen token
+// alone cannot set one.
+func (u *UserUsecase) SetPIN(ctx context.Context, userID int, req model.SetPINRequest) error {
+	user, err := u.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return ErrWrongPassword
+	}
+
+	state, err := u.UserRepo.GetPINState(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if state.Hash != "" {
+		return ErrPINAlreadySet
+	}
+
+	return u.storePIN(ctx, userID, req.PIN)
+}
+
+func (u *UserUsecase) ChangePIN(ctx context.Context, userID int, req model.ChangePINRequest) error {
+	if err := u.VerifyPIN(ctx, userID, req.OldPIN); err != nil {
+		return err
+	}
+	return u.storePIN(ctx, userID, req.NewPIN)
+}
+
+// VerifyPIN checks the transaction PIN. Too many wrong attempts lock it for a while,
+// even the correct PIN is refused during the lock.
+func (u *UserUsecase) VerifyPIN(ctx context.Context, userID int, pin string) error {
+	state, err := u.UserRepo.GetPINState(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if state.Hash == "" {
+		return ErrPINNotSet
+	}
+	if state.LockedUntil != nil && time.Now().Before(*state.LockedUntil) {
+		return ErrPINLocked
+	}
+
+	// the attempt is counted before the (slow) comparison, so parallel requests cannot
+	// get more than maxPINAttempts guesses in
+	attempt, err := u.UserRepo.ClaimPINAttempt(ctx, userID, maxPINAttempts, pinLockDuration)
+	if err != nil {
+		return err
+	}
+
+	// hash pin like password
+	if err := bcrypt.CompareHashAndPassword([]byte(attempt.Hash), []byte(pin)); err != nil {
+		if attempt.LockedUntil != nil {
+			return ErrPINLocked
+		}
+		return ErrPINInvalid
+	}
+
+	return u.UserRepo.ResetPINFailures(ctx, userID)
+}
+
+func (u *UserUsecase) storePIN(ctx context.Context, userID int, pin string) error {
+	hashedPIN, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return u.UserRepo.SetPIN(ctx, userID, string(hashedPIN))
+}
+
+// EnrollTOTP creates a new secret. 2FA only becomes active after ConfirmTOTP, so an
+// abandoned enrollment does not lock the user out.
+func (u *UserUsecase) EnrollTOTP(ctx context.Context, userID int) (model.TOTPEnrollResponse, error) {
+	state, err := u.UserRepo.GetTOTPState(ctx, userID)
+	if err != nil {
+		return model.TOTPEnrollResponse{}, err
+	}
+	if state.Enabled {
+		return model.TOTPEnrollResponse{}, ErrTwoFactorAlreadyEnabled
+	}
+
+	user, err := u.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		return model.TOTPEnrollResponse{}, err
+	}
+
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return model.TOTPEnrollResponse{}, err
+	}
+	if err := u.UserRepo.SetTOTPSecret(ctx, userID, secret); err != nil {
+		return model.TOTPEnrollResponse{}, err
+	}
+
+	return model.TOTPEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(totpIssuer(), user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP activates 2FA with the first code from the app and returns fresh recovery codes.
+func (u *UserUsecase) ConfirmTOTP(ctx context.Context, userID int, req model.TOTPConfirmRequest) (model.TOTPConfirmResponse, error) {
+	state, err := u.UserRepo.GetTOTPState(ctx, userID)
+	if err != nil {
+		return model.TOTPConfirmResponse{}, err
+	}
+	if state.Enabled {
+		return model.TOTPConfirmResponse{}, ErrTwoFactorAlreadyEnabled
+	}
+	if state.Secret == "" {
+		return model.TOTPConfirmResponse{}, ErrTwoFactorNotEnrolled
+	}
+	if err := u.checkOTP(ctx, userID, state, req.OTP); err != nil {
+		return model.TOTPConfirmResponse{}, err
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return model.TOTPConfirmResponse{}, err
+		}
+		codes[i] = code
+		hashes[i] = hashToken(normalizeRecoveryCode(code))
+	}
+
+	if err := u.UserRepo.EnableTOTP(ctx, userID, hashes); err != nil {
+		return model.TOTPConfirmResponse{}, err
+	}
+	return model.TOTPConfirmResponse{RecoveryCodes: codes}, nil
+}
+
+func (u *UserUsecase) DisableTOTP(ctx context.Context, userID int, req model.DisableTOTPRequest) error {
+	user, err := u.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return ErrWrongPassword
+	}
+	if err := u.VerifyOTP(ctx, userID, req.OTP); err != nil {
+		return err
+	}
+	return u.UserRepo.DisableTOTP(ctx, userID)
+}
+
+// VerifyOTP checks a code of an enabled 2FA. Every code is accepted once only.
+func (u *UserUsecase) VerifyOTP(ctx context.Context, userID int, code string) error {
+	state, err := u.UserRepo.GetTOTPState(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !state.Enabled {
+		return ErrTwoFactorNotEnabled
+	}
+	return u.checkOTP(ctx, userID, state, code)
+}
+
+// checkOTP counts the attempt before the code is compared, like VerifyPIN: a 6-digit code must not be
+// open to guessing by whoever knows the PIN or the password.
+func (u *UserUsecase) checkOTP(ctx context.Context, userID int, state *model.TOTPState, code string) error {
+	lockedUntil, err := u.UserRepo.ClaimOTPAttempt(ctx, userID, maxOTPAttempts, otpLockDuration)
+	if err != nil {
+		return err
+	}
+
+	step, ok := totp.Validate(state.Secret, code, time.Now())
+	if !ok || step <= state.LastStep {
+		if lockedUntil != nil {
+			return ErrOTPLocked
+		}
+		return ErrOTPInvalid
+	}
+
+	// the repository re-checks the step atomically, two requests racing with one code lose one
+	fresh, err := u.UserRepo.MarkTOTPStepUsed(ctx, userID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrOTPInvalid
+	}
+	return u.UserRepo.ResetOTPFailures(ctx, userID)
+}
+
+// recovery codes look like "abcd-efgh-ijkl-mnop" (80 random bits)
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	s := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+	return s[0:4] + "-" + s[4:8] + "-" + s[8:12] + "-" + s[12:16], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+func totpIssuer() string {
+	if issuer := os.Getenv("TOTP_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "E-Wallet"
+}
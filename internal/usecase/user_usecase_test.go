@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"ewallet-service/internal/model"
+	"ewallet-service/internal/repository"
 	"ewallet-service/internal/repository/mocks"
+	"ewallet-service/internal/totp"
 	"ewallet-service/internal/usecase"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -17,7 +20,7 @@ func TestRegister_Success(t *testing.T) {
 	// arrange
 	mockRepo := new(mocks.UserRepositoryMock)
 
-	u := usecase.NewUserUsecase(mockRepo)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys))
 
 	// data dummy
 	req := model.RegisterRequest{
@@ -27,7 +30,7 @@ func TestRegister_Success(t *testing.T) {
 	}
 	expectedWallet := model.Wallet{
 		WalletNumber: "10012345",
-		Balance:      0,
+		Balance:      model.NewMoney(0),
 	}
 
 	mockRepo.On("EmailExists", mock.Anything, req.Email).Return(false, nil)
@@ -48,7 +51,7 @@ func TestRegister_Success(t *testing.T) {
 func TestRegister_EmailDuplicate(t *testing.T) {
 	// arrange
 	mockRepo := new(mocks.UserRepositoryMock)
-	u := usecase.NewUserUsecase(mockRepo)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys))
 
 	req := model.RegisterRequest{
 		Name:     "Duplikat",
@@ -62,15 +65,15 @@ func TestRegister_EmailDuplicate(t *testing.T) {
 	res, err := u.Register(context.Background(), req)
 
 	// assert
-	assert.Error(t, err)
-	assert.Equal(t, "Email ada@example.com sudah terdaftar", err.Error())
+	assert.ErrorIs(t, err, repository.ErrEmailTaken)
 	assert.Empty(t, res.ID)
 }
 
 func TestLogin_Success(t *testing.T) {
 	// arrange
 	mockRepo := new(mocks.UserRepositoryMock)
-	u := usecase.NewUserUsecase(mockRepo)
+	mockSession := new(mocks.SessionRepositoryMock)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(mockSession, testKeys))
 
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
 
@@ -86,20 +89,24 @@ func TestLogin_Success(t *testing.T) {
 	}
 
 	mockRepo.On("FindByEmail", mock.Anything, req.Email).Return(dummyUser, nil)
+	mockSession.On("CreateSession", mock.Anything, mock.AnythingOfType("*model.Session"), mock.AnythingOfType("string")).Return(nil)
 
 	// act
 	res, err := u.Login(context.Background(), req)
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, res.AccessToken)
+	assert.NotEmpty(t, res.RefreshToken)
 	assert.Equal(t, "Bearer", res.Type)
+	assert.Equal(t, "15m", res.ExpiresIn)
+	mockSession.AssertExpectations(t)
 
 }
 
 func TestLogin_WrongPassword(t *testing.T) {
 	// arrange
 	mockRepo := new(mocks.UserRepositoryMock)
-	u := usecase.NewUserUsecase(mockRepo)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys))
 
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("passwordBenar"), bcrypt.DefaultCost)
 
@@ -120,21 +127,40 @@ func TestLogin_WrongPassword(t *testing.T) {
 	res, err := u.Login(context.Background(), req)
 
 	// assert
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "Email atau password salah")
+	assert.ErrorIs(t, err, usecase.ErrInvalidCredentials)
+	assert.Empty(t, res.AccessToken)
+}
+
+func TestLogin_FrozenAccount(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.UserRepositoryMock)
+	mockSession := new(mocks.SessionRepositoryMock)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(mockSession, testKeys))
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("rahasia123"), bcrypt.DefaultCost)
+	dummyUser := &model.User{ID: 1, Email: "test@example.com", Password: string(hashedPassword), Frozen: true}
+
+	mockRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(dummyUser, nil)
+
+	// act
+	res, err := u.Login(context.Background(), model.LoginRequest{Email: "test@example.com", Password: "rahasia123"})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrAccountFrozen)
 	assert.Empty(t, res.AccessToken)
+	mockSession.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestGetBalance_Success(t *testing.T)  {
 	// arrange
 	mockRepo := new(mocks.UserRepositoryMock)
-	u := usecase.NewUserUsecase(mockRepo)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys))
 
 	userID := 1
 	expectedWallet := &model.Wallet{
 		ID: 1,
 		UserID: userID,
-		Balance: 150000,
+		Balance: model.NewMoney(150000),
 		WalletNumber: "100888",
 	}
 
@@ -147,7 +173,7 @@ func TestGetBalance_Success(t *testing.T)  {
 	// assert
 	assert.NoError(t, err)
 	assert.NotNil(t, res)
-	assert.Equal(t, float64(150000), res.Balance)
+	assert.Equal(t, model.NewMoney(150000), res.Balance)
 	assert.Equal(t, "100888", res.WalletNumber)
 
 	mockRepo.AssertExpectations(t)
@@ -155,7 +181,7 @@ func TestGetBalance_Success(t *testing.T)  {
 
 func TestGetBalance_Error(t *testing.T)  {
 	mockRepo := new(mocks.UserRepositoryMock)
-	u := usecase.NewUserUsecase(mockRepo)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys))
 
 	userID := 99
 	expectedErr := errors.New("Database connection failed")
@@ -173,3 +199,358 @@ func TestGetBalance_Error(t *testing.T)  {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestSetPIN_Success(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.UserRepositoryMock)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys))
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	mockRepo.On("FindByID", mock.Anything, 1).Return(&model.User{ID: 1, Password: string(hashedPassword)}, nil)
+	mockRepo.On("GetPINState", mock.Anything, 1).Return(&model.PINState{}, nil)
+	mockRepo.On("SetPIN", mock.Anything, 1, mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte("123456")) == nil
+	})).Return(nil)
+
+	// act
+	err := u.SetPIN(context.Background(), 1, model.SetPINRequest{Password: "password123", PIN: "123456"})
+
+	// assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestVerifyPIN_WrongPINRecordsFailure(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.UserRepositoryMock)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys))
+
+	hashedPIN, _ := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.DefaultCost)
+	mockRepo.On("GetPINState", mock.Anything, 1).Return(&model.PINState{Hash: string(hashedPIN)}, nil)
+	mockRepo.On("ClaimPINAttempt", mock.Anything, 1, 5, 30*time.Minute).Return(&model.PINState{Hash: string(hashedPIN), FailedAttempts: 1}, nil)
+
+	// act
+	err := u.VerifyPIN(context.Background(), 1, "654321")
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrPINInvalid)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestVerifyPIN_LastAttemptLocks(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.UserRepositoryMock)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys))
+
+	hashedPIN, _ := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.DefaultCost)
+	lockedUntil := time.Now().Add(30 * time.Minute)
+	mockRepo.On("GetPINState", mock.Anything, 1).Return(&model.PINState{Hash: string(hashedPIN), FailedAttempts: 4}, nil)
+	mockRepo.On("ClaimPINAttempt", mock.Anything, 1, 5, 30*time.Minute).Return(&model.PINState{Hash: string(hashedPIN), LockedUntil: &lockedUntil}, nil)
+
+	// act
+	err := u.VerifyPIN(context.Background(), 1, "654321")
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrPINLocked)
+}
+
+func TestVerifyPIN_LockedRejectsCorrectPIN(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.UserRepositoryMock)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys))
+
+	hashedPIN, _ := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.DefaultCost)
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	mockRepo.On("GetPINState", mock.Anything, 1).Return(&model.PINState{Hash: string(hashedPIN), LockedUntil: &lockedUntil}, nil)
+
+	// act
+	err := u.VerifyPIN(context.Background(), 1, "123456")
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrPINLocked)
+	mockRepo.AssertNotCalled(t, "ResetPINFailures", mock.Anything, mock.Anything)
+}
+
+func TestVerifyPIN_SuccessResetsFailures(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.UserRepositoryMock)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys))
+
+	hashedPIN, _ := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.DefaultCost)
+	mockRepo.On("GetPINState", mock.Anything, 1).Return(&model.PINState{Hash: string(hashedPIN), FailedAttempts: 2}, nil)
+	mockRepo.On("ClaimPINAttempt", mock.Anything, 1, 5, 30*time.Minute).Return(&model.PINState{Hash: string(hashedPIN), FailedAttempts: 3}, nil)
+	mockRepo.On("ResetPINFailures", mock.Anything, 1).Return(nil)
+
+	// act
+	err := u.VerifyPIN(context.Background(), 1, "123456")
+
+	// assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestVerifyPIN_ParallelAttemptAfterLockIsRefused(t *testing.T) {
+	// arrange: the lock was set by another request between the state read and the claim
+	mockRepo := new(mocks.UserRepositoryMock)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys))
+
+	hashedPIN, _ := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.DefaultCost)
+	mockRepo.On("GetPINState", mock.Anything, 1).Return(&model.PINState{Hash: string(hashedPIN), FailedAttempts: 4}, nil)
+	mockRepo.On("ClaimPINAttempt", mock.Anything, 1, 5, 30*time.Minute).Return(nil, repository.ErrPINLocked)
+
+	// act
+	err := u.VerifyPIN(context.Background(), 1, "123456")
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrPINLocked)
+	mockRepo.AssertNotCalled(t, "ResetPINFailures", mock.Anything, mock.Anything)
+}
+
+func TestLogin_TwoFactorReturnsChallenge(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.UserRepositoryMock)
+	mockSession := new(mocks.SessionRepositoryMock)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(mockSession, testKeys))
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	mockRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(&model.User{ID: 1, Password: string(hashedPassword), TOTPEnabled: true}, nil)
+
+	// act
+	res, err := u.Login(context.Background(), model.LoginRequest{Email: "test@example.com", Password: "password123"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.True(t, res.TwoFactorRequired)
+	assert.NotEmpty(t, res.ChallengeToken)
+	assert.Empty(t, res.AccessToken)
+	mockSession.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// allowOTPAttempt lets 2FA code checks through the attempt counter.
+func allowOTPAttempt(repo *mocks.UserRepositoryMock) {
+	repo.On("ClaimOTPAttempt", mock.Anything, mock.Anything, 5, 30*time.Minute).Return(nil, nil).Maybe()
+	repo.On("ResetOTPFailures", mock.Anything, mock.Anything).Return(nil).Maybe()
+}
+
+func TestVerifyOTP_LastAttemptLocks(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.UserRepositoryMock)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys))
+
+	secret, _ := totp.NewSecret()
+	lockedUntil := time.Now().Add(30 * time.Minute)
+	mockRepo.On("GetTOTPState", mock.Anything, 1).Return(&model.TOTPState{Secret: secret, Enabled: true}, nil)
+	mockRepo.On("ClaimOTPAttempt", mock.Anything, 1, 5, 30*time.Minute).Return(&lockedUntil, nil)
+
+	// act
+	err := u.VerifyOTP(context.Background(), 1, "000000")
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrOTPLocked)
+	mockRepo.AssertNotCalled(t, "MarkTOTPStepUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVerifyOTP_LockedRefusesCorrectCode(t *testing.T) {
+	// arrange: the PIN was right, the 2FA code has been guessed at too often
+	mockRepo := new(mocks.UserRepositoryMock)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys))
+
+	secret, _ := totp.NewSecret()
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+	mockRepo.On("GetTOTPState", mock.Anything, 1).Return(&model.TOTPState{Secret: secret, Enabled: true}, nil)
+	mockRepo.On("ClaimOTPAttempt", mock.Anything, 1, 5, 30*time.Minute).Return(nil, repository.ErrOTPLocked)
+
+	// act
+	err := u.VerifyOTP(context.Background(), 1, code)
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrOTPLocked)
+	mockRepo.AssertNotCalled(t, "MarkTOTPStepUsed", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "ResetOTPFailures", mock.Anything, mock.Anything)
+}
+
+func TestVerifyOTP_SuccessResetsFailures(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.UserRepositoryMock)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys))
+
+	secret, _ := totp.NewSecret()
+	step := totp.Step(time.Now())
+	code, _ := totp.Code(secret, step)
+	mockRepo.On("GetTOTPState", mock.Anything, 1).Return(&model.TOTPState{Secret: secret, Enabled: true}, nil)
+	mockRepo.On("ClaimOTPAttempt", mock.Anything, 1, 5, 30*time.Minute).Return(nil, nil)
+	mockRepo.On("MarkTOTPStepUsed", mock.Anything, 1, step).Return(true, nil)
+	mockRepo.On("ResetOTPFailures", mock.Anything, 1).Return(nil)
+
+	// act
+	err := u.VerifyOTP(context.Background(), 1, code)
+
+	// assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLoginTwoFactor_WithOTP(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.UserRepositoryMock)
+	mockSession := new(mocks.SessionRepositoryMock)
+	auth := usecase.NewAuthUsecase(mockSession, testKeys)
+	u := usecase.NewUserUsecase(mockRepo, auth)
+
+	user := &model.User{ID: 1, Email: "test@example.com", TOTPEnabled: true}
+	challenge, _ := auth.IssueChallenge(user)
+
+	secret, _ := totp.NewSecret()
+	step := totp.Step(time.Now())
+	code, _ := totp.Code(secret, step)
+
+	mockRepo.On("GetTOTPState", mock.Anything, 1).Return(&model.TOTPState{Secret: secret, Enabled: true}, nil)
+	allowOTPAttempt(mockRepo)
+	mockRepo.On("MarkTOTPStepUsed", mock.Anything, 1, step).Return(true, nil)
+	mockRepo.On("FindByID", mock.Anything, 1).Return(user, nil)
+	mockSession.On("ConsumeToken", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(true, nil)
+	mockSession.On("CreateSession", mock.Anything, mock.AnythingOfType("*model.Session"), mock.AnythingOfType("string")).Return(nil)
+
+	// act
+	res, err := u.LoginTwoFactor(context.Background(), model.LoginTwoFactorRequest{ChallengeToken: challenge, OTP: code})
+
+	// assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, res.AccessToken)
+	mockRepo.AssertExpectations(t)
+	mockSession.AssertExpectations(t)
+}
+
+func TestLoginTwoFactor_ChallengeIsSingleUse(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.UserRepositoryMock)
+	mockSession := new(mocks.SessionRepositoryMock)
+	auth := usecase.NewAuthUsecase(mockSession, testKeys)
+	u := usecase.NewUserUsecase(mockRepo, auth)
+
+	user := &model.User{ID: 1, Email: "test@example.com", TOTPEnabled: true}
+	challenge, _ := auth.IssueChallenge(user)
+
+	secret, _ := totp.NewSecret()
+	step := totp.Step(time.Now())
+	code, _ := totp.Code(secret, step)
+
+	mockRepo.On("GetTOTPState", mock.Anything, 1).Return(&model.TOTPState{Secret: secret, Enabled: true}, nil)
+	allowOTPAttempt(mockRepo)
+	mockRepo.On("MarkTOTPStepUsed", mock.Anything, 1, step).Return(true, nil)
+	mockRepo.On("FindByID", mock.Anything, 1).Return(user, nil)
+	// the challenge was already spent by an earlier login
+	mockSession.On("ConsumeToken", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	// act
+	res, err := u.LoginTwoFactor(context.Background(), model.LoginTwoFactorRequest{ChallengeToken: challenge, OTP: code})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrTokenInvalid)
+	assert.Empty(t, res.AccessToken)
+	mockSession.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLoginTwoFactor_ReplayedOTP(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.UserRepositoryMock)
+	auth := usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys)
+	u := usecase.NewUserUsecase(mockRepo, auth)
+
+	challenge, _ := auth.IssueChallenge(&model.User{ID: 1})
+
+	secret, _ := totp.NewSecret()
+	step := totp.Step(time.Now())
+	code, _ := totp.Code(secret, step)
+
+	// the code's step was already accepted once
+	mockRepo.On("FindByID", mock.Anything, 1).Return(&model.User{ID: 1, TOTPEnabled: true}, nil)
+	mockRepo.On("GetTOTPState", mock.Anything, 1).Return(&model.TOTPState{Secret: secret, Enabled: true, LastStep: step}, nil)
+	allowOTPAttempt(mockRepo)
+
+	// act
+	_, err := u.LoginTwoFactor(context.Background(), model.LoginTwoFactorRequest{ChallengeToken: challenge, OTP: code})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrOTPInvalid)
+	mockRepo.AssertNotCalled(t, "MarkTOTPStepUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLoginTwoFactor_UsedRecoveryCode(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.UserRepositoryMock)
+	auth := usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys)
+	u := usecase.NewUserUsecase(mockRepo, auth)
+
+	challenge, _ := auth.IssueChallenge(&model.User{ID: 1})
+	mockRepo.On("FindByID", mock.Anything, 1).Return(&model.User{ID: 1, TOTPEnabled: true}, nil)
+	mockRepo.On("GetTOTPState", mock.Anything, 1).Return(&model.TOTPState{Secret: "secret", Enabled: true}, nil)
+	mockRepo.On("UseRecoveryCode", mock.Anything, 1, mock.AnythingOfType("string")).Return(false, nil)
+
+	// act
+	_, err := u.LoginTwoFactor(context.Background(), model.LoginTwoFactorRequest{ChallengeToken: challenge, RecoveryCode: "abcd-efgh-ijkl-mnop"})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrRecoveryCodeInvalid)
+}
+
+func TestLoginTwoFactor_RecoveryCodeAfterDisable(t *testing.T) {
+	// arrange: 2FA was turned off after the password step
+	mockRepo := new(mocks.UserRepositoryMock)
+	auth := usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys)
+	u := usecase.NewUserUsecase(mockRepo, auth)
+
+	challenge, _ := auth.IssueChallenge(&model.User{ID: 1})
+	mockRepo.On("FindByID", mock.Anything, 1).Return(&model.User{ID: 1}, nil)
+	mockRepo.On("GetTOTPState", mock.Anything, 1).Return(&model.TOTPState{}, nil)
+
+	// act
+	_, err := u.LoginTwoFactor(context.Background(), model.LoginTwoFactorRequest{ChallengeToken: challenge, RecoveryCode: "abcd-efgh-ijkl-mnop"})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrTwoFactorNotEnabled)
+	mockRepo.AssertNotCalled(t, "UseRecoveryCode", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLoginTwoFactor_AccessTokenIsNotAChallenge(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.UserRepositoryMock)
+	mockSession := new(mocks.SessionRepositoryMock)
+	auth := usecase.NewAuthUsecase(mockSession, testKeys)
+	u := usecase.NewUserUsecase(mockRepo, auth)
+
+	mockSession.On("CreateSession", mock.Anything, mock.AnythingOfType("*model.Session"), mock.AnythingOfType("string")).Return(nil)
+	tokens, _ := auth.IssueTokens(context.Background(), &model.User{ID: 1})
+
+	// act
+	_, err := u.LoginTwoFactor(context.Background(), model.LoginTwoFactorRequest{ChallengeToken: tokens.AccessToken, OTP: "123456"})
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrTokenInvalid)
+}
+
+func TestConfirmTOTP_ReturnsRecoveryCodes(t *testing.T) {
+	// arrange
+	mockRepo := new(mocks.UserRepositoryMock)
+	u := usecase.NewUserUsecase(mockRepo, usecase.NewAuthUsecase(new(mocks.SessionRepositoryMock), testKeys))
+
+	secret, _ := totp.NewSecret()
+	step := totp.Step(time.Now())
+	code, _ := totp.Code(secret, step)
+
+	mockRepo.On("GetTOTPState", mock.Anything, 1).Return(&model.TOTPState{Secret: secret}, nil)
+	allowOTPAttempt(mockRepo)
+	mockRepo.On("MarkTOTPStepUsed", mock.Anything, 1, step).Return(true, nil)
+	mockRepo.On("EnableTOTP", mock.Anything, 1, mock.MatchedBy(func(hashes []string) bool {
+		return len(hashes) == 10
+	})).Return(nil)
+
+	// act
+	res, err := u.ConfirmTOTP(context.Background(), 1, model.TOTPConfirmRequest{OTP: code})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, res.RecoveryCodes, 10)
+	assert.Regexp(t, `^[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}$`, res.RecoveryCodes[0])
+	mockRepo.AssertExpectations(t)
+}